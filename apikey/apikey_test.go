@@ -0,0 +1,98 @@
+package apikey
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Dev-Umb/go-pkg/errno"
+	"github.com/gin-gonic/gin"
+)
+
+func newRouter(store KeyStore) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/orders", Middleware(Options{Store: store, AllowPlainKey: true}), func(c *gin.Context) {
+		key, _ := CurrentKey(c)
+		var body map[string]interface{}
+		_ = c.ShouldBindJSON(&body)
+		c.JSON(http.StatusOK, gin.H{"code": 0, "owner": key.Owner, "item": body["item"]})
+	})
+	return r
+}
+
+func serve(r *gin.Engine, req *http.Request) map[string]interface{} {
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	var body map[string]interface{}
+	_ = json.Unmarshal(w.Body.Bytes(), &body)
+	return body
+}
+
+func TestSignedRequest(t *testing.T) {
+	apiKey, key, err := Issue("partner")
+	if err != nil {
+		t.Fatalf("issue: %v", err)
+	}
+	r := newRouter(NewMemoryKeyStore(key))
+
+	req := httptest.NewRequest(http.MethodPost, "/orders?b=2&a=1", strings.NewReader(`{"item":"x"}`))
+	if err := SignRequest(req, apiKey); err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+	if body := serve(r, req); body["owner"] != "partner" || body["item"] != "x" {
+		t.Fatalf("signed request failed: %v", body)
+	}
+
+	// 重放同一个随机数
+	replay := httptest.NewRequest(http.MethodPost, "/orders?b=2&a=1", strings.NewReader(`{"item":"x"}`))
+	replay.Header = req.Header.Clone()
+	if body := serve(r, replay); body["code"] != float64(errno.ErrNonceReplayed.Code) {
+		t.Fatalf("expected replay rejection, got %v", body)
+	}
+
+	// 篡改请求体
+	tampered := httptest.NewRequest(http.MethodPost, "/orders?b=2&a=1", strings.NewReader(`{"item":"y"}`))
+	tampered.Header = req.Header.Clone()
+	tampered.Header.Set(HeaderNonce, "other")
+	if body := serve(r, tampered); body["code"] != float64(errno.ErrSignatureMismatch.Code) {
+		t.Fatalf("expected signature mismatch, got %v", body)
+	}
+
+	// 过期时间戳
+	stale := httptest.NewRequest(http.MethodPost, "/orders", nil)
+	_ = SignRequest(stale, apiKey)
+	stale.Header.Set(HeaderTimestamp, strconv.FormatInt(time.Now().Add(-time.Hour).Unix(), 10))
+	if body := serve(r, stale); body["code"] != float64(errno.ErrTimestampExpired.Code) {
+		t.Fatalf("expected expired timestamp, got %v", body)
+	}
+}
+
+func TestPlainKey(t *testing.T) {
+	apiKey, key, err := Issue("partner")
+	if err != nil {
+		t.Fatalf("issue: %v", err)
+	}
+	r := newRouter(NewMemoryKeyStore(key))
+
+	req := httptest.NewRequest(http.MethodPost, "/orders", nil)
+	req.Header.Set(HeaderAPIKey, apiKey)
+	if body := serve(r, req); body["owner"] != "partner" {
+		t.Fatalf("plain key failed: %v", body)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/orders", nil)
+	req.Header.Set(HeaderAPIKey, key.ID+".wrong")
+	if body := serve(r, req); body["code"] != float64(errno.ErrAPIKeyInvalid.Code) {
+		t.Fatalf("expected invalid key, got %v", body)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/orders", nil)
+	if body := serve(r, req); body["code"] != float64(errno.ErrAPIKeyMissing.Code) {
+		t.Fatalf("expected missing key, got %v", body)
+	}
+}
@@ -0,0 +1,133 @@
+// Package apikey 基于API密钥和HMAC请求签名的认证，适用于无法使用JWT的合作方接入
+package apikey
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Dev-Umb/go-pkg/util"
+)
+
+// KeyIDPrefix API密钥ID前缀
+const KeyIDPrefix = "ak_"
+
+// Key API密钥存储记录，不包含明文密钥
+type Key struct {
+	ID         string    `json:"id"`                   // 密钥ID，公开，随请求发送
+	Owner      string    `json:"owner"`                // 所属合作方或应用
+	Hash       string    `json:"hash"`                 // 明文密钥的 bcrypt 哈希，用于直接携带密钥的认证
+	SigningKey string    `json:"signing_key"`          // HMAC签名密钥，由明文密钥派生，见 SigningKeyOf
+	Scopes     []string  `json:"scopes,omitempty"`     // 权限范围
+	Disabled   bool      `json:"disabled,omitempty"`   // 是否已停用
+	ExpiresAt  time.Time `json:"expires_at,omitempty"` // 过期时间，零值表示不过期
+}
+
+// Valid 判断密钥是否可用
+func (k *Key) Valid(now time.Time) bool {
+	return k != nil && !k.Disabled && (k.ExpiresAt.IsZero() || now.Before(k.ExpiresAt))
+}
+
+// Issue 生成API密钥，返回交给合作方的明文密钥（格式为 "<ID>.<secret>"，只在此时可见）和用于存储的记录
+func Issue(owner string, scopes ...string) (string, *Key, error) {
+	id := make([]byte, 12)
+	secret := make([]byte, 32)
+	if _, err := rand.Read(id); err != nil {
+		return "", nil, err
+	}
+	if _, err := rand.Read(secret); err != nil {
+		return "", nil, err
+	}
+
+	key := &Key{
+		ID:     KeyIDPrefix + hex.EncodeToString(id),
+		Owner:  owner,
+		Scopes: scopes,
+	}
+	secretText := base64.RawURLEncoding.EncodeToString(secret)
+	hash, err := util.HashPassword(secretText)
+	if err != nil {
+		return "", nil, err
+	}
+	key.Hash = hash
+	key.SigningKey = SigningKeyOf(secretText)
+	return key.ID + "." + secretText, key, nil
+}
+
+// SplitKey 拆分明文密钥为密钥ID和secret
+func SplitKey(apiKey string) (id, secret string, ok bool) {
+	id, secret, ok = strings.Cut(apiKey, ".")
+	if !ok || !strings.HasPrefix(id, KeyIDPrefix) || secret == "" {
+		return "", "", false
+	}
+	return id, secret, true
+}
+
+// SigningKeyOf 由明文密钥的secret部分派生HMAC签名密钥
+// 服务端只保存派生密钥，泄露后可伪造签名但无法还原明文密钥；客户端使用 SignRequest 时会自动派生
+func SigningKeyOf(secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("apikey-signing"))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify 校验明文secret是否与记录匹配
+func (k *Key) Verify(secret string) bool {
+	return util.CheckPasswordHash(secret, k.Hash)
+}
+
+// ErrKeyNotFound 密钥不存在
+var ErrKeyNotFound = errors.New("api key not found")
+
+// KeyStore API密钥存储，通常由业务方基于数据库实现
+type KeyStore interface {
+	// Lookup 按密钥ID查找，不存在时返回 ErrKeyNotFound
+	Lookup(ctx context.Context, id string) (*Key, error)
+}
+
+// MemoryKeyStore 进程内密钥存储，适用于配置文件加载的少量密钥和测试
+type MemoryKeyStore struct {
+	mu   sync.RWMutex
+	keys map[string]*Key
+}
+
+// NewMemoryKeyStore 创建进程内密钥存储
+func NewMemoryKeyStore(keys ...*Key) *MemoryKeyStore {
+	s := &MemoryKeyStore{keys: make(map[string]*Key)}
+	for _, key := range keys {
+		s.Put(key)
+	}
+	return s
+}
+
+// Put 保存密钥
+func (s *MemoryKeyStore) Put(key *Key) {
+	s.mu.Lock()
+	s.keys[key.ID] = key
+	s.mu.Unlock()
+}
+
+// Delete 删除密钥
+func (s *MemoryKeyStore) Delete(id string) {
+	s.mu.Lock()
+	delete(s.keys, id)
+	s.mu.Unlock()
+}
+
+// Lookup 按密钥ID查找
+func (s *MemoryKeyStore) Lookup(ctx context.Context, id string) (*Key, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	key, ok := s.keys[id]
+	if !ok {
+		return nil, ErrKeyNotFound
+	}
+	return key, nil
+}
@@ -0,0 +1,189 @@
+package apikey
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"errors"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/Dev-Umb/go-pkg/core"
+	"github.com/Dev-Umb/go-pkg/errno"
+	"github.com/Dev-Umb/go-pkg/logger"
+	"github.com/gin-gonic/gin"
+)
+
+// KeyContextKey gin上下文中存储 *Key 的键
+const KeyContextKey = "apiKey"
+
+// 默认配置
+const (
+	DefaultMaxSkew      = 5 * time.Minute
+	DefaultMaxBodyBytes = 10 << 20
+)
+
+// keyCtxKey 请求上下文中存储 *Key 的键
+type keyCtxKey struct{}
+
+// Options 认证中间件配置
+type Options struct {
+	// Store 密钥存储，必填
+	Store KeyStore
+	// Nonces 随机数存储，为空时使用进程内存储；多实例部署应使用 RedisNonceStore
+	Nonces NonceStore
+	// MaxSkew 允许的时间戳偏差，默认5分钟，随机数保留两倍时长
+	MaxSkew time.Duration
+	// MaxBodyBytes 参与签名的请求体上限，默认10MB
+	MaxBodyBytes int64
+	// AllowPlainKey 允许通过 X-API-Key 直接携带明文密钥（不签名），只应在HTTPS下开启
+	AllowPlainKey bool
+}
+
+// Middleware API密钥认证中间件
+// 签名请求需携带 X-Access-Key、X-Timestamp、X-Nonce、X-Signature，签名内容见 CanonicalString
+// 认证通过后可通过 CurrentKey 获取密钥记录
+func Middleware(opts Options) gin.HandlerFunc {
+	if opts.Nonces == nil {
+		opts.Nonces = NewMemoryNonceStore()
+	}
+	if opts.MaxSkew <= 0 {
+		opts.MaxSkew = DefaultMaxSkew
+	}
+	if opts.MaxBodyBytes <= 0 {
+		opts.MaxBodyBytes = DefaultMaxBodyBytes
+	}
+
+	return func(c *gin.Context) {
+		var key *Key
+		var err error
+		if c.GetHeader(HeaderSignature) != "" {
+			key, err = verifySigned(c, opts)
+		} else if opts.AllowPlainKey && c.GetHeader(HeaderAPIKey) != "" {
+			key, err = verifyPlain(c, opts)
+		} else {
+			err = errno.ErrAPIKeyMissing
+		}
+		if err != nil {
+			logger.Warnf(c, "api key auth failed: %v", err)
+			core.SendResponse(c, err, nil)
+			c.Abort()
+			return
+		}
+
+		c.Set(KeyContextKey, key)
+		c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), keyCtxKey{}, key))
+		c.Next()
+	}
+}
+
+// lookupKey 查找可用的密钥
+func lookupKey(ctx context.Context, store KeyStore, id string) (*Key, error) {
+	key, err := store.Lookup(ctx, id)
+	if errors.Is(err, ErrKeyNotFound) {
+		return nil, errno.ErrAPIKeyInvalid
+	}
+	if err != nil {
+		return nil, errno.New(errno.InternalServerError, err)
+	}
+	if !key.Valid(time.Now()) {
+		return nil, errno.ErrAPIKeyInvalid
+	}
+	return key, nil
+}
+
+// verifyPlain 校验明文密钥
+func verifyPlain(c *gin.Context, opts Options) (*Key, error) {
+	id, secret, ok := SplitKey(c.GetHeader(HeaderAPIKey))
+	if !ok {
+		return nil, errno.ErrAPIKeyInvalid
+	}
+	key, err := lookupKey(c.Request.Context(), opts.Store, id)
+	if err != nil {
+		return nil, err
+	}
+	if !key.Verify(secret) {
+		return nil, errno.ErrAPIKeyInvalid
+	}
+	return key, nil
+}
+
+// verifySigned 校验签名请求：时间戳、签名、随机数依次校验，签名通过后才记录随机数
+func verifySigned(c *gin.Context, opts Options) (*Key, error) {
+	id := c.GetHeader(HeaderAccessKey)
+	timestamp := c.GetHeader(HeaderTimestamp)
+	nonce := c.GetHeader(HeaderNonce)
+	signature := c.GetHeader(HeaderSignature)
+	if id == "" || timestamp == "" || nonce == "" {
+		return nil, errno.ErrAPIKeyMissing
+	}
+
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return nil, errno.ErrTimestampExpired
+	}
+	if skew := time.Since(time.Unix(ts, 0)); skew > opts.MaxSkew || skew < -opts.MaxSkew {
+		return nil, errno.ErrTimestampExpired
+	}
+
+	ctx := c.Request.Context()
+	key, err := lookupKey(ctx, opts.Store, id)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := readLimitedBody(c, opts.MaxBodyBytes)
+	if err != nil {
+		return nil, err
+	}
+	canonical := CanonicalString(c.Request.Method, c.Request.URL.EscapedPath(), c.Request.URL.Query().Encode(), timestamp, nonce, body)
+	if !hmac.Equal([]byte(Sign(key.SigningKey, canonical)), []byte(signature)) {
+		return nil, errno.ErrSignatureMismatch
+	}
+
+	fresh, err := opts.Nonces.Use(ctx, id+":"+nonce, 2*opts.MaxSkew)
+	if err != nil {
+		return nil, errno.New(errno.InternalServerError, err)
+	}
+	if !fresh {
+		return nil, errno.ErrNonceReplayed
+	}
+	return key, nil
+}
+
+// readLimitedBody 读取请求体并恢复，超过上限时拒绝
+func readLimitedBody(c *gin.Context, limit int64) ([]byte, error) {
+	if c.Request.Body == nil {
+		return nil, nil
+	}
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, limit+1))
+	c.Request.Body.Close()
+	if err != nil {
+		return nil, errno.New(errno.BindRequestError, err)
+	}
+	if int64(len(body)) > limit {
+		return nil, errno.ValueOutOfRangeError
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+// CurrentKey 获取当前请求的API密钥，未认证时返回 false
+func CurrentKey(c *gin.Context) (*Key, bool) {
+	if value, exists := c.Get(KeyContextKey); exists {
+		if key, ok := value.(*Key); ok {
+			return key, true
+		}
+	}
+	return KeyFromContext(c.Request.Context())
+}
+
+// KeyFromContext 从context中获取API密钥
+func KeyFromContext(ctx context.Context) (*Key, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+	key, ok := ctx.Value(keyCtxKey{}).(*Key)
+	return key, ok
+}
@@ -0,0 +1,64 @@
+package apikey
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// NonceStore 请求随机数存储，用于防重放
+type NonceStore interface {
+	// Use 记录随机数并保留 ttl，返回 false 表示该随机数已被使用
+	Use(ctx context.Context, nonce string, ttl time.Duration) (bool, error)
+}
+
+// MemoryNonceStore 进程内随机数存储，适用于单实例部署和测试
+type MemoryNonceStore struct {
+	mu     sync.Mutex
+	nonces map[string]time.Time
+	now    func() time.Time
+}
+
+// NewMemoryNonceStore 创建进程内随机数存储
+func NewMemoryNonceStore() *MemoryNonceStore {
+	return &MemoryNonceStore{nonces: make(map[string]time.Time), now: time.Now}
+}
+
+// Use 记录随机数
+func (s *MemoryNonceStore) Use(ctx context.Context, nonce string, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	for n, exp := range s.nonces {
+		if !now.Before(exp) {
+			delete(s.nonces, n)
+		}
+	}
+	if _, ok := s.nonces[nonce]; ok {
+		return false, nil
+	}
+	s.nonces[nonce] = now.Add(ttl)
+	return true, nil
+}
+
+// RedisNonceStore 基于Redis的随机数存储，适用于多实例部署
+type RedisNonceStore struct {
+	rdb    redis.UniversalClient
+	prefix string
+}
+
+// NewRedisNonceStore 创建Redis随机数存储，prefix 为空时使用 "apikey:nonce:"
+func NewRedisNonceStore(rdb redis.UniversalClient, prefix string) *RedisNonceStore {
+	if prefix == "" {
+		prefix = "apikey:nonce:"
+	}
+	return &RedisNonceStore{rdb: rdb, prefix: prefix}
+}
+
+// Use 使用 SET NX 记录随机数
+func (s *RedisNonceStore) Use(ctx context.Context, nonce string, ttl time.Duration) (bool, error) {
+	return s.rdb.SetNX(ctx, s.prefix+nonce, 1, ttl).Result()
+}
@@ -0,0 +1,94 @@
+package apikey
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 签名请求头
+const (
+	HeaderAPIKey    = "X-API-Key"    // 直接携带明文密钥 "<ID>.<secret>"
+	HeaderAccessKey = "X-Access-Key" // 签名请求的密钥ID
+	HeaderTimestamp = "X-Timestamp"  // Unix时间戳（秒）
+	HeaderNonce     = "X-Nonce"      // 随机数，同一密钥在有效期内不可重复
+	HeaderSignature = "X-Signature"  // 十六进制HMAC-SHA256签名
+)
+
+// CanonicalString 待签名字符串，各部分以换行分隔：
+//
+//	METHOD
+//	/path?sorted=query
+//	timestamp
+//	nonce
+//	hex(sha256(body))
+func CanonicalString(method, path, query, timestamp, nonce string, body []byte) string {
+	digest := sha256.Sum256(body)
+	uri := path
+	if query != "" {
+		uri += "?" + query
+	}
+	return strings.Join([]string{
+		strings.ToUpper(method),
+		uri,
+		timestamp,
+		nonce,
+		hex.EncodeToString(digest[:]),
+	}, "\n")
+}
+
+// Sign 使用签名密钥计算签名
+func Sign(signingKey, canonical string) string {
+	mac := hmac.New(sha256.New, []byte(signingKey))
+	mac.Write([]byte(canonical))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// SignRequest 为出站请求签名，apiKey 为 Issue 返回的明文密钥，供合作方SDK或测试使用
+func SignRequest(req *http.Request, apiKey string) error {
+	id, secret, ok := SplitKey(apiKey)
+	if !ok {
+		return errors.New("API密钥格式错误")
+	}
+
+	body, err := readBody(req)
+	if err != nil {
+		return err
+	}
+
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	nonceText := hex.EncodeToString(nonce)
+	canonical := CanonicalString(req.Method, req.URL.EscapedPath(), req.URL.Query().Encode(), timestamp, nonceText, body)
+
+	req.Header.Set(HeaderAccessKey, id)
+	req.Header.Set(HeaderTimestamp, timestamp)
+	req.Header.Set(HeaderNonce, nonceText)
+	req.Header.Set(HeaderSignature, Sign(SigningKeyOf(secret), canonical))
+	return nil
+}
+
+// readBody 读取请求体并恢复，便于后续处理再次读取
+func readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
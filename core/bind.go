@@ -0,0 +1,192 @@
+package core
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/Dev-Umb/go-pkg/errno"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/zh"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	entranslations "github.com/go-playground/validator/v10/translations/en"
+	zhtranslations "github.com/go-playground/validator/v10/translations/zh"
+)
+
+// FieldError 单个字段的校验失败明细
+type FieldError struct {
+	Field   string `json:"field"`           // 字段名，优先使用 json/form 标签名
+	Rule    string `json:"rule"`            // 校验规则，如 required、max
+	Param   string `json:"param,omitempty"` // 校验规则参数，如 max=10 中的 10
+	Message string `json:"message"`         // 本地化后的错误描述
+}
+
+// 属于“缺少参数”的校验规则，命中时使用 MissingParamError
+var missingRules = map[string]bool{
+	"required":             true,
+	"required_if":          true,
+	"required_unless":      true,
+	"required_with":        true,
+	"required_with_all":    true,
+	"required_without":     true,
+	"required_without_all": true,
+}
+
+var (
+	uni       *ut.UniversalTranslator
+	transOnce sync.Once
+)
+
+// initTranslator 为 gin 默认校验器注册中英文错误翻译
+func initTranslator() {
+	transOnce.Do(func() {
+		zhLocale := zh.New()
+		uni = ut.New(zhLocale, zhLocale, en.New())
+
+		v, ok := binding.Validator.Engine().(*validator.Validate)
+		if !ok {
+			return
+		}
+		zhTrans, _ := uni.GetTranslator("zh")
+		_ = zhtranslations.RegisterDefaultTranslations(v, zhTrans)
+		enTrans, _ := uni.GetTranslator("en")
+		_ = entranslations.RegisterDefaultTranslations(v, enTrans)
+	})
+}
+
+// getTranslator 根据 Accept-Language 选择翻译器，默认中文
+func getTranslator(c *gin.Context) ut.Translator {
+	initTranslator()
+
+	var locales []string
+	for _, part := range strings.Split(c.GetHeader("Accept-Language"), ",") {
+		lang := strings.TrimSpace(strings.SplitN(part, ";", 2)[0])
+		if lang == "" {
+			continue
+		}
+		lang = strings.ToLower(strings.ReplaceAll(lang, "-", "_"))
+		locales = append(locales, lang)
+		if idx := strings.Index(lang, "_"); idx > 0 {
+			locales = append(locales, lang[:idx])
+		}
+	}
+	trans, _ := uni.FindTranslator(locales...)
+	return trans
+}
+
+// Bind 绑定请求参数并校验，失败时返回可直接交给 SendResponse 的错误
+// 校验失败返回 InvalidParamsError(14001) 或 MissingParamError(14002)，data 为字段明细列表
+func Bind(c *gin.Context, obj interface{}) error {
+	return bindWith(c, obj, c.ShouldBind(obj))
+}
+
+// BindWith 使用指定的 binding 绑定请求参数，错误处理同 Bind
+func BindWith(c *gin.Context, obj interface{}, b binding.Binding) error {
+	return bindWith(c, obj, c.ShouldBindWith(obj, b))
+}
+
+func bindWith(c *gin.Context, obj interface{}, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return errno.New(errno.ErrBind, err)
+	}
+
+	fieldErrors := TranslateValidationErrors(c, validationErrors, obj)
+	target := errno.InvalidParamsError
+	messages := make([]string, 0, len(fieldErrors))
+	for _, fe := range fieldErrors {
+		if missingRules[fe.Rule] {
+			target = errno.MissingParamError
+		}
+		messages = append(messages, fe.Message)
+	}
+
+	return &errno.Err{
+		Code:    target.Code,
+		Message: target.Message + ": " + strings.Join(messages, "; "),
+		Err:     err.Error(),
+		Data:    fieldErrors,
+	}
+}
+
+// TranslateValidationErrors 将校验错误转换为字段明细
+// obj 为绑定目标，用于把结构体字段名还原为 json/form 标签名，可为 nil
+func TranslateValidationErrors(c *gin.Context, errs validator.ValidationErrors, obj interface{}) []FieldError {
+	trans := getTranslator(c)
+
+	var objType reflect.Type
+	if obj != nil {
+		objType = reflect.TypeOf(obj)
+	}
+
+	result := make([]FieldError, 0, len(errs))
+	for _, fe := range errs {
+		field := fe.Field()
+		if objType != nil {
+			if name := taggedFieldName(objType, fe.StructNamespace()); name != "" {
+				field = name
+			}
+		}
+		result = append(result, FieldError{
+			Field:   field,
+			Rule:    fe.Tag(),
+			Param:   fe.Param(),
+			Message: fe.Translate(trans),
+		})
+	}
+	return result
+}
+
+// taggedFieldName 按结构体命名空间（如 Req.Items[0].Name）逐级查找标签名，返回 items[0].name 形式
+func taggedFieldName(t reflect.Type, namespace string) string {
+	parts := strings.Split(namespace, ".")
+	if len(parts) < 2 {
+		return ""
+	}
+
+	names := make([]string, 0, len(parts)-1)
+	for _, part := range parts[1:] {
+		for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array || t.Kind() == reflect.Map {
+			t = t.Elem()
+		}
+		if t.Kind() != reflect.Struct {
+			return ""
+		}
+
+		fieldName, index := part, ""
+		if idx := strings.Index(part, "["); idx > 0 {
+			fieldName, index = part[:idx], part[idx:]
+		}
+		sf, ok := t.FieldByName(fieldName)
+		if !ok {
+			return ""
+		}
+		t = sf.Type
+		// 未打标签的匿名嵌入字段在 JSON 中是平铺的，不计入路径
+		if sf.Anonymous && sf.Tag.Get("json") == "" && sf.Tag.Get("form") == "" {
+			continue
+		}
+		names = append(names, tagName(sf)+index)
+	}
+	return strings.Join(names, ".")
+}
+
+// tagName 依次取 json、form、uri 标签，都没有时使用字段名
+func tagName(sf reflect.StructField) string {
+	for _, key := range []string{"json", "form", "uri"} {
+		name := strings.SplitN(sf.Tag.Get(key), ",", 2)[0]
+		if name != "" && name != "-" {
+			return name
+		}
+	}
+	return sf.Name
+}
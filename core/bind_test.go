@@ -0,0 +1,71 @@
+package core
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Dev-Umb/go-pkg/errno"
+
+	"github.com/gin-gonic/gin"
+)
+
+type bindItem struct {
+	Name string `json:"name" binding:"required"`
+}
+
+type bindRequest struct {
+	UserName string     `json:"user_name" binding:"required"`
+	Age      int        `json:"age" binding:"gte=1,lte=150"`
+	Items    []bindItem `json:"items" binding:"dive"`
+}
+
+func newBindContext(body, lang string) *gin.Context {
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Request.Header.Set("Accept-Language", lang)
+	return c
+}
+
+func TestBindValidationErrors(t *testing.T) {
+	var req bindRequest
+	err := Bind(newBindContext(`{"age":200,"items":[{}]}`, "en-US,en;q=0.9"), &req)
+
+	e, ok := err.(*errno.Err)
+	if !ok {
+		t.Fatalf("expected *errno.Err, got %#v", err)
+	}
+	if e.Code != errno.MissingParamError.Code {
+		t.Fatalf("expected code %d, got %d", errno.MissingParamError.Code, e.Code)
+	}
+
+	fields := map[string]FieldError{}
+	for _, fe := range e.Data.([]FieldError) {
+		fields[fe.Field] = fe
+	}
+	if fe, ok := fields["age"]; !ok || fe.Rule != "lte" || fe.Param != "150" {
+		t.Fatalf("unexpected age violation: %#v", fields)
+	}
+	if _, ok := fields["items[0].name"]; !ok {
+		t.Fatalf("expected nested field items[0].name, got %#v", fields)
+	}
+	if !strings.Contains(fields["user_name"].Message, "required") {
+		t.Fatalf("expected english message, got %q", fields["user_name"].Message)
+	}
+}
+
+func TestBindInvalidParams(t *testing.T) {
+	var req bindRequest
+	err := Bind(newBindContext(`{"user_name":"a","age":0}`, ""), &req)
+	if code, _, _ := errno.DecodeErr(err); code != errno.InvalidParamsError.Code {
+		t.Fatalf("expected code %d, got %d", errno.InvalidParamsError.Code, code)
+	}
+
+	err = Bind(newBindContext(`{`, ""), &req)
+	if code, _, _ := errno.DecodeErr(err); code != errno.ErrBind.Code {
+		t.Fatalf("expected code %d, got %d", errno.ErrBind.Code, code)
+	}
+}
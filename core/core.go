@@ -2,12 +2,6 @@ package core
 
 import (
 	"context"
-	"encoding/json"
-	"github.com/Dev-Umb/go-pkg/errno"
-	"net/http"
-	"time"
-
-	"github.com/Dev-Umb/go-pkg/logger"
 
 	"github.com/gin-gonic/gin"
 )
@@ -58,45 +52,9 @@ func GetTraceIDFromGin(c *gin.Context) string {
 	return GetTraceID(c.Request.Context())
 }
 
+// SendResponse 使用默认响应发送器发送响应，可通过 SetDefaultResponder 定制
 func SendResponse(c *gin.Context, err error, data interface{}) {
-	// 获取traceID，优先从gin上下文中获取
-	traceID := GetTraceIDFromGin(c)
-
-	// 计算请求耗时
-	var elapsed time.Duration
-	if startTime, exists := c.Get(StartTimeKey); exists {
-		if st, ok := startTime.(time.Time); ok {
-			elapsed = time.Since(st)
-		}
-	}
-
-	code, _, message := errno.DecodeErr(err)
-
-	// 构建响应对象
-	response := Response{
-		Code:    code,
-		Message: message,
-		TraceID: traceID,
-		Data:    data,
-		I18n:    "",
-	}
-
-	// 记录响应信息
-	responseBytes, _ := json.Marshal(response)
-
-	// 记录详细的响应日志
-	if err != nil {
-		// 如果有错误，记录错误信息
-		logger.Infof(c, "[%s] Response error: code=%d, message=%s, elapsed=%v",
-			traceID, code, message, elapsed)
-	} else {
-		// 正常响应
-		logger.Infof(c, "[%s] Response completed: code=%d, elapsed=%v, data=%s",
-			traceID, code, elapsed, string(responseBytes))
-	}
-
-	// 发送响应
-	c.JSON(http.StatusOK, response)
+	defaultResponder.Send(c, err, data)
 }
 
 //func SendBasicResponse(c *gin.Context, err error)  {
@@ -0,0 +1,384 @@
+package core
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/Dev-Umb/go-pkg/errno"
+	"github.com/Dev-Umb/go-pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+// cursorSecret 游标签名密钥，需通过 InitCursorSecret 设置，多实例部署时各实例需相同
+// 未设置时使用进程内随机生成的密钥，游标不能伪造，但不能跨实例或重启后使用
+var (
+	cursorSecretMu         sync.RWMutex
+	cursorSecret           []byte
+	cursorSecretWarnedOnce sync.Once
+)
+
+// InitCursorSecret 设置游标签名密钥，空密钥不生效
+func InitCursorSecret(secret string) {
+	if secret == "" {
+		return
+	}
+	cursorSecretMu.Lock()
+	cursorSecret = []byte(secret)
+	cursorSecretMu.Unlock()
+}
+
+// getCursorSecret 返回游标签名密钥，未设置时生成随机密钥
+func getCursorSecret() ([]byte, error) {
+	cursorSecretMu.RLock()
+	secret := cursorSecret
+	cursorSecretMu.RUnlock()
+	if secret != nil {
+		return secret, nil
+	}
+
+	cursorSecretMu.Lock()
+	defer cursorSecretMu.Unlock()
+	if cursorSecret == nil {
+		random := make([]byte, 32)
+		if _, err := rand.Read(random); err != nil {
+			return nil, err
+		}
+		cursorSecret = random
+		cursorSecretWarnedOnce.Do(func() {
+			logger.Warnf(context.Background(), "游标签名密钥未设置，使用进程内随机密钥，游标不能跨实例使用，请调用 InitCursorSecret")
+		})
+	}
+	return cursorSecret, nil
+}
+
+// SortField 排序字段
+type SortField struct {
+	Field string
+	Desc  bool
+}
+
+// PageRequest 分页请求参数，支持页码分页（page/size）和游标分页（cursor/size）
+// sort 形如 "-created_at,id"，前缀 "-" 表示倒序
+type PageRequest struct {
+	Page   int      `form:"page" json:"page"`
+	Size   int      `form:"size" json:"size"`
+	Cursor string   `form:"cursor" json:"cursor"`
+	Sort   []string `form:"sort" json:"sort"`
+
+	sorts      []SortField
+	offset     int
+	nextCursor string
+}
+
+// PageConfig 分页参数约束
+type PageConfig struct {
+	DefaultSize  int
+	MaxSize      int
+	MaxPage      int      // 0 表示不限制
+	AllowedSorts []string // 为空表示不允许排序
+	DefaultSort  []string
+	CustomCursor bool // 接口通过 SetNextCursor 使用自定义游标
+}
+
+// PageOption 分页配置选项
+type PageOption func(*PageConfig)
+
+// WithPageSize 设置默认和最大分页大小
+func WithPageSize(defaultSize, maxSize int) PageOption {
+	return func(c *PageConfig) {
+		c.DefaultSize = defaultSize
+		c.MaxSize = maxSize
+	}
+}
+
+// WithMaxPage 设置允许的最大页码，避免深度分页
+func WithMaxPage(maxPage int) PageOption {
+	return func(c *PageConfig) {
+		c.MaxPage = maxPage
+	}
+}
+
+// WithSorts 设置允许的排序字段和默认排序
+func WithSorts(allowed []string, defaultSort ...string) PageOption {
+	return func(c *PageConfig) {
+		c.AllowedSorts = allowed
+		c.DefaultSort = defaultSort
+	}
+}
+
+// WithCustomCursor 声明接口使用 SetNextCursor 签发的自定义游标（如 keyset 分页）
+// 未声明时只接受按偏移量生成的游标，避免自定义游标被当作偏移量 0 返回首页
+func WithCustomCursor() PageOption {
+	return func(c *PageConfig) {
+		c.CustomCursor = true
+	}
+}
+
+// PageResponse 统一的分页响应结构
+type PageResponse struct {
+	List       interface{} `json:"list"`
+	Total      int64       `json:"total"`
+	Page       int         `json:"page,omitempty"`
+	Size       int         `json:"size"`
+	HasMore    bool        `json:"has_more"`
+	NextCursor string      `json:"next_cursor,omitempty"`
+}
+
+// offsetCursorKind 偏移量游标的类型标记，用于与自定义游标区分
+const offsetCursorKind = "offset"
+
+// offsetCursor 默认游标内容，记录下一页的偏移量
+type offsetCursor struct {
+	Kind   string `json:"k"`
+	Offset int    `json:"o"`
+}
+
+// newOffsetCursor 生成偏移量游标
+func newOffsetCursor(offset int) offsetCursor {
+	return offsetCursor{Kind: offsetCursorKind, Offset: offset}
+}
+
+// BindPage 从查询参数中绑定分页请求并校验边界
+func BindPage(c *gin.Context, opts ...PageOption) (*PageRequest, error) {
+	config := &PageConfig{
+		DefaultSize: DefaultPageSize,
+		MaxSize:     MaxPageSize,
+	}
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	req := &PageRequest{}
+	if err := BindWith(c, req, binding.Query); err != nil {
+		return nil, err
+	}
+	if err := req.normalize(config); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+// normalize 填充默认值并校验分页参数
+func (p *PageRequest) normalize(config *PageConfig) error {
+	if p.Page < 0 || p.Size < 0 {
+		return errno.New(errno.ValueOutOfRangeError, fmt.Errorf("page和size不能为负数"))
+	}
+	if p.Size == 0 {
+		p.Size = config.DefaultSize
+	}
+	if p.Size > config.MaxSize {
+		return errno.New(errno.ValueOutOfRangeError, fmt.Errorf("size不能超过%d", config.MaxSize))
+	}
+
+	if p.Cursor != "" {
+		var cur struct {
+			Kind   string `json:"k"`
+			Offset *int   `json:"o"`
+		}
+		if err := DecodeCursor(p.Cursor, &cur); err != nil {
+			return err
+		}
+		p.Page = 0
+		p.offset = 0
+		switch {
+		case cur.Kind == offsetCursorKind:
+			if cur.Offset == nil || *cur.Offset < 0 {
+				return errno.New(errno.ValueOutOfRangeError, fmt.Errorf("游标偏移量无效"))
+			}
+			p.offset = *cur.Offset
+		case !config.CustomCursor:
+			return errno.New(errno.InvalidParamsError, errors.New("接口不支持该游标"))
+		}
+	} else {
+		if p.Page == 0 {
+			p.Page = 1
+		}
+		if config.MaxPage > 0 && p.Page > config.MaxPage {
+			return errno.New(errno.ValueOutOfRangeError, fmt.Errorf("page不能超过%d", config.MaxPage))
+		}
+		if p.Size > 0 && p.Page-1 > math.MaxInt/p.Size {
+			return errno.New(errno.ValueOutOfRangeError, fmt.Errorf("page超出范围"))
+		}
+		p.offset = (p.Page - 1) * p.Size
+	}
+
+	sorts := p.Sort
+	if len(sorts) == 0 {
+		sorts = config.DefaultSort
+	}
+	allowed := make(map[string]bool, len(config.AllowedSorts))
+	for _, field := range config.AllowedSorts {
+		allowed[field] = true
+	}
+	p.sorts = nil
+	for _, item := range sorts {
+		for _, field := range strings.Split(item, ",") {
+			field = strings.TrimSpace(field)
+			if field == "" {
+				continue
+			}
+			sf := SortField{Field: field}
+			if strings.HasPrefix(field, "-") {
+				sf = SortField{Field: field[1:], Desc: true}
+			} else if strings.HasPrefix(field, "+") {
+				sf.Field = field[1:]
+			}
+			if !allowed[sf.Field] {
+				return errno.New(errno.InvalidParamsError, fmt.Errorf("不支持的排序字段: %s", sf.Field))
+			}
+			p.sorts = append(p.sorts, sf)
+		}
+	}
+	return nil
+}
+
+// IsCursor 是否为游标分页
+func (p *PageRequest) IsCursor() bool {
+	return p.Cursor != ""
+}
+
+// Offset 查询偏移量，自定义游标时为 0，需通过 DecodeCursor 解析游标
+func (p *PageRequest) Offset() int {
+	return p.offset
+}
+
+// Limit 查询条数
+func (p *PageRequest) Limit() int {
+	return p.Size
+}
+
+// SortFields 解析后的排序字段
+func (p *PageRequest) SortFields() []SortField {
+	return p.sorts
+}
+
+// SetNextCursor 设置自定义的下一页游标（如基于主键的 keyset 分页），v 会被签名编码
+// 未设置时默认按偏移量生成下一页游标，自定义游标可通过 DecodeCursor(p.Cursor, &v) 解析
+// 使用自定义游标的接口需在 BindPage 时传入 WithCustomCursor
+func (p *PageRequest) SetNextCursor(v interface{}) error {
+	cursor, err := EncodeCursor(v)
+	if err != nil {
+		return err
+	}
+	p.nextCursor = cursor
+	return nil
+}
+
+// NewPageResponse 构建分页响应，total 小于 0 表示总数未知
+func NewPageResponse(items interface{}, count int, total int64, p *PageRequest) *PageResponse {
+	resp := &PageResponse{
+		List:  items,
+		Total: total,
+		Page:  p.Page,
+		Size:  p.Size,
+	}
+
+	next := int64(p.offset + count)
+	if total >= 0 {
+		resp.HasMore = next < total
+	} else {
+		resp.HasMore = count >= p.Size
+	}
+
+	// 还有下一页时总是返回游标，首页以页码方式请求的客户端也可以切换到游标分页
+	if resp.HasMore {
+		if p.nextCursor != "" {
+			resp.NextCursor = p.nextCursor
+		} else {
+			resp.NextCursor, _ = EncodeCursor(newOffsetCursor(int(next)))
+		}
+	}
+	return resp
+}
+
+// SendPage 以统一的分页结构发送响应
+// items 需为切片，total 小于 0 表示总数未知
+func SendPage(c *gin.Context, err error, items interface{}, total int64, p *PageRequest) {
+	if err != nil || p == nil {
+		SendResponse(c, err, nil)
+		return
+	}
+	if items == nil {
+		items = []interface{}{}
+	}
+	SendResponse(c, nil, NewPageResponse(items, sliceLen(items), total, p))
+}
+
+// sliceLen 获取切片长度，非切片按单条处理
+func sliceLen(items interface{}) int {
+	v := reflect.ValueOf(items)
+	if v.Kind() == reflect.Slice || v.Kind() == reflect.Array {
+		return v.Len()
+	}
+	return 1
+}
+
+// EncodeCursor 将游标内容编码为带签名的不透明字符串
+func EncodeCursor(v interface{}) (string, error) {
+	payload, err := json.Marshal(v)
+	if err != nil {
+		return "", errno.New(errno.ConvertError, err)
+	}
+	sign, err := signCursor(payload)
+	if err != nil {
+		return "", errno.New(errno.ConvertError, err)
+	}
+	return base64.RawURLEncoding.EncodeToString(payload) + "." +
+		base64.RawURLEncoding.EncodeToString(sign), nil
+}
+
+// DecodeCursor 校验游标签名并解码到 v，被篡改的游标返回 InvalidParamsError
+func DecodeCursor(cursor string, v interface{}) error {
+	invalid := errno.New(errno.InvalidParamsError, errors.New("无效的游标"))
+
+	parts := strings.SplitN(cursor, ".", 2)
+	if len(parts) != 2 {
+		return invalid
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return invalid
+	}
+	sign, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return invalid
+	}
+	expected, err := signCursor(payload)
+	if err != nil {
+		return errno.New(errno.ConvertError, err)
+	}
+	if !hmac.Equal(sign, expected) {
+		return invalid
+	}
+	if err := json.Unmarshal(payload, v); err != nil {
+		return invalid
+	}
+	return nil
+}
+
+func signCursor(payload []byte) ([]byte, error) {
+	secret, err := getCursorSecret()
+	if err != nil {
+		return nil, err
+	}
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(payload)
+	return mac.Sum(nil)[:16], nil
+}
@@ -0,0 +1,114 @@
+package core
+
+import (
+	"encoding/base64"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/Dev-Umb/go-pkg/errno"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newPageContext(query string) *gin.Context {
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodGet, "/?"+query, nil)
+	return c
+}
+
+func TestBindPage(t *testing.T) {
+	p, err := BindPage(newPageContext("page=3&size=10&sort=-created_at,id"), WithSorts([]string{"created_at", "id"}))
+	if err != nil {
+		t.Fatalf("bind page: %v", err)
+	}
+	if p.Offset() != 20 || p.Limit() != 10 {
+		t.Fatalf("unexpected offset/limit: %d/%d", p.Offset(), p.Limit())
+	}
+	sorts := p.SortFields()
+	if len(sorts) != 2 || !sorts[0].Desc || sorts[0].Field != "created_at" || sorts[1].Desc {
+		t.Fatalf("unexpected sorts: %#v", sorts)
+	}
+
+	resp := NewPageResponse([]int{1, 2}, 2, 22, p)
+	if resp.HasMore || resp.NextCursor != "" {
+		t.Fatalf("unexpected page response: %#v", resp)
+	}
+}
+
+func TestBindPageOutOfRange(t *testing.T) {
+	for _, query := range []string{"size=1000", "page=-1", "page=20&size=10"} {
+		_, err := BindPage(newPageContext(query), WithMaxPage(10))
+		if code, _, _ := errno.DecodeErr(err); code != errno.ValueOutOfRangeError.Code {
+			t.Fatalf("%s: expected %d, got %d", query, errno.ValueOutOfRangeError.Code, code)
+		}
+	}
+	// 不限制页码时，偏移量溢出的页码也要拒绝
+	_, err := BindPage(newPageContext("page=" + strconv.Itoa(math.MaxInt/10+2) + "&size=10"))
+	if code, _, _ := errno.DecodeErr(err); code != errno.ValueOutOfRangeError.Code {
+		t.Fatalf("overflow page: expected %d, got %d", errno.ValueOutOfRangeError.Code, code)
+	}
+
+	_, err = BindPage(newPageContext("sort=password"), WithSorts([]string{"id"}))
+	if code, _, _ := errno.DecodeErr(err); code != errno.InvalidParamsError.Code {
+		t.Fatalf("expected %d, got %d", errno.InvalidParamsError.Code, code)
+	}
+}
+
+func TestCursorPaging(t *testing.T) {
+	first, _ := EncodeCursor(newOffsetCursor(0))
+	p, err := BindPage(newPageContext("size=2&cursor=" + first))
+	if err != nil {
+		t.Fatalf("bind page: %v", err)
+	}
+
+	resp := NewPageResponse([]int{1, 2}, 2, -1, p)
+	if !resp.HasMore || resp.NextCursor == "" {
+		t.Fatalf("expected next cursor: %#v", resp)
+	}
+
+	var cur offsetCursor
+	if err := DecodeCursor(resp.NextCursor, &cur); err != nil || cur.Offset != 2 {
+		t.Fatalf("decode cursor: %v %#v", err, cur)
+	}
+
+	payload := base64.RawURLEncoding.EncodeToString([]byte(`{"k":"offset","o":999}`))
+	tampered := payload + resp.NextCursor[strings.Index(resp.NextCursor, "."):]
+	if err := DecodeCursor(tampered, &cur); err == nil {
+		t.Fatalf("expected tampered cursor to be rejected")
+	}
+}
+
+func TestCustomCursorPaging(t *testing.T) {
+	type keysetCursor struct {
+		LastID int64 `json:"last_id"`
+	}
+	keyset, _ := EncodeCursor(keysetCursor{LastID: 42})
+
+	// 未声明自定义游标的接口不能把 keyset 游标当作偏移量 0
+	if _, err := BindPage(newPageContext("cursor=" + keyset)); err == nil {
+		t.Fatalf("expected custom cursor to be rejected by offset paging")
+	}
+
+	p, err := BindPage(newPageContext("cursor="+keyset), WithCustomCursor())
+	if err != nil {
+		t.Fatalf("bind page: %v", err)
+	}
+	var cur keysetCursor
+	if err := DecodeCursor(p.Cursor, &cur); err != nil || cur.LastID != 42 || p.Offset() != 0 {
+		t.Fatalf("decode custom cursor: %v %#v offset=%d", err, cur, p.Offset())
+	}
+
+	// 没有类型标记的游标不能当作偏移量游标
+	untagged, _ := EncodeCursor(map[string]int{"o": 4})
+	if _, err := BindPage(newPageContext("cursor=" + untagged)); err == nil {
+		t.Fatalf("expected untagged cursor to be rejected by offset paging")
+	}
+	if p, err := BindPage(newPageContext("cursor="+untagged), WithCustomCursor()); err != nil || p.Offset() != 0 {
+		t.Fatalf("untagged cursor should not set offset: %v", err)
+	}
+}
@@ -0,0 +1,230 @@
+package core
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Dev-Umb/go-pkg/errno"
+	"github.com/Dev-Umb/go-pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/gin-gonic/gin/render"
+	"google.golang.org/protobuf/proto"
+)
+
+// skipBodyLogKey 路由级别关闭响应体日志的上下文键
+const skipBodyLogKey = "core.skipBodyLog"
+
+// DefaultLogBodyLimit 默认响应体日志最大字节数，0表示不限制，与原有行为一致，需要时通过 WithLogBodyLimit 开启截断
+const DefaultLogBodyLimit = 0
+
+// EnvelopeFunc 将响应内容包装为最终输出的结构
+type EnvelopeFunc func(resp Response) interface{}
+
+// EnvelopeKeys 自定义响应结构的键名，键名为空表示不输出该字段
+type EnvelopeKeys struct {
+	Code    string
+	Success string
+	Message string
+	Data    string
+	I18n    string
+	TraceID string
+}
+
+// DefaultEnvelope 默认响应结构，即 Response 本身
+func DefaultEnvelope(resp Response) interface{} {
+	return resp
+}
+
+// KeysEnvelope 按自定义键名输出响应结构，Success 为 code 是否等于 0
+func KeysEnvelope(keys EnvelopeKeys) EnvelopeFunc {
+	return func(resp Response) interface{} {
+		out := make(map[string]interface{}, 6)
+		if keys.Code != "" {
+			out[keys.Code] = resp.Code
+		}
+		if keys.Success != "" {
+			out[keys.Success] = resp.Code == errno.OK.Code
+		}
+		if keys.Message != "" {
+			out[keys.Message] = resp.Message
+		}
+		if keys.Data != "" && resp.Data != nil {
+			out[keys.Data] = resp.Data
+		}
+		if keys.I18n != "" && resp.I18n != nil && resp.I18n != "" {
+			out[keys.I18n] = resp.I18n
+		}
+		if keys.TraceID != "" {
+			out[keys.TraceID] = resp.TraceID
+		}
+		return out
+	}
+}
+
+// Responder 可配置的响应发送器
+type Responder struct {
+	envelope     EnvelopeFunc
+	formats      []string
+	logBody      bool
+	logBodyLimit int
+}
+
+// ResponderOption 响应发送器配置选项
+type ResponderOption func(*Responder)
+
+// WithEnvelope 自定义响应结构
+func WithEnvelope(envelope EnvelopeFunc) ResponderOption {
+	return func(r *Responder) {
+		r.envelope = envelope
+	}
+}
+
+// WithEnvelopeKeys 使用自定义键名的响应结构
+func WithEnvelopeKeys(keys EnvelopeKeys) ResponderOption {
+	return WithEnvelope(KeysEnvelope(keys))
+}
+
+// WithFormats 设置按 Accept 协商的输出格式，第一个为默认格式
+// 支持 binding.MIMEJSON、binding.MIMEPROTOBUF、binding.MIMEMSGPACK
+// protobuf 仅在 EnvelopeFunc 返回 proto.Message 时生效，否则回退为 JSON
+func WithFormats(formats ...string) ResponderOption {
+	return func(r *Responder) {
+		if len(formats) > 0 {
+			r.formats = formats
+		}
+	}
+}
+
+// WithLogBodyLimit 设置响应体日志最大字节数，超出部分截断，小于等于0表示不限制
+func WithLogBodyLimit(limit int) ResponderOption {
+	return func(r *Responder) {
+		r.logBodyLimit = limit
+	}
+}
+
+// WithoutBodyLog 不记录响应体，只记录 code 和耗时
+func WithoutBodyLog() ResponderOption {
+	return func(r *Responder) {
+		r.logBody = false
+	}
+}
+
+// NewResponder 创建响应发送器
+func NewResponder(opts ...ResponderOption) *Responder {
+	r := &Responder{
+		envelope:     DefaultEnvelope,
+		formats:      []string{binding.MIMEJSON},
+		logBody:      true,
+		logBodyLimit: DefaultLogBodyLimit,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+var defaultResponder = NewResponder()
+
+// SetDefaultResponder 替换 SendResponse 使用的默认响应发送器，应在服务启动时调用
+func SetDefaultResponder(r *Responder) {
+	if r != nil {
+		defaultResponder = r
+	}
+}
+
+// SkipBodyLog 路由中间件，关闭该路由的响应体日志，适用于大响应或敏感数据
+func SkipBodyLog() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(skipBodyLogKey, true)
+		c.Next()
+	}
+}
+
+// Send 发送响应
+func (r *Responder) Send(c *gin.Context, err error, data interface{}) {
+	// 获取traceID，优先从gin上下文中获取
+	traceID := GetTraceIDFromGin(c)
+
+	// 计算请求耗时
+	var elapsed time.Duration
+	if startTime, exists := c.Get(StartTimeKey); exists {
+		if st, ok := startTime.(time.Time); ok {
+			elapsed = time.Since(st)
+		}
+	}
+
+	code, _, message := errno.DecodeErr(err)
+
+	// 错误未显式传入数据时，使用错误自身附带的数据（如参数校验明细）
+	if data == nil {
+		if e, ok := err.(*errno.Err); ok && e.Data != nil {
+			data = e.Data
+		}
+	}
+
+	// 构建响应对象
+	body := r.envelope(Response{
+		Code:    code,
+		Message: message,
+		TraceID: traceID,
+		Data:    data,
+		I18n:    "",
+	})
+
+	// 记录详细的响应日志
+	if err != nil {
+		// 如果有错误，记录错误信息
+		logger.Infof(c, "[%s] Response error: code=%d, message=%s, elapsed=%v",
+			traceID, code, message, elapsed)
+	} else if r.logBody && !c.GetBool(skipBodyLogKey) {
+		// 正常响应
+		logger.Infof(c, "[%s] Response completed: code=%d, elapsed=%v, data=%s",
+			traceID, code, elapsed, r.logBodyString(body))
+	} else {
+		logger.Infof(c, "[%s] Response completed: code=%d, elapsed=%v",
+			traceID, code, elapsed)
+	}
+
+	// 发送响应
+	r.render(c, body)
+}
+
+// render 按 Accept 协商的格式输出
+func (r *Responder) render(c *gin.Context, body interface{}) {
+	format := r.formats[0]
+	if len(r.formats) > 1 {
+		if negotiated := c.NegotiateFormat(r.formats...); negotiated != "" {
+			format = negotiated
+		}
+	}
+
+	switch format {
+	case binding.MIMEPROTOBUF:
+		if msg, ok := body.(proto.Message); ok {
+			c.Render(http.StatusOK, render.ProtoBuf{Data: msg})
+			return
+		}
+	case binding.MIMEMSGPACK, binding.MIMEMSGPACK2:
+		c.Render(http.StatusOK, render.MsgPack{Data: body})
+		return
+	}
+	c.JSON(http.StatusOK, body)
+}
+
+// logBodyString 序列化响应体用于日志，超出限制时截断
+func (r *Responder) logBodyString(body interface{}) string {
+	responseBytes, _ := json.Marshal(body)
+	if r.logBodyLimit > 0 && len(responseBytes) > r.logBodyLimit {
+		cut := r.logBodyLimit
+		// 避免截断在多字节字符中间
+		for cut > 0 && !utf8.RuneStart(responseBytes[cut]) {
+			cut--
+		}
+		return string(responseBytes[:cut]) + "...(truncated)"
+	}
+	return string(responseBytes)
+}
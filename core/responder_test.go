@@ -0,0 +1,74 @@
+package core
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Dev-Umb/go-pkg/errno"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+func TestResponderEnvelopeKeys(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := NewResponder(WithEnvelopeKeys(EnvelopeKeys{
+		Success: "success",
+		Message: "msg",
+		Data:    "result",
+		TraceID: "request_id",
+	}))
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Send(c, errno.ErrUserNotFound, nil)
+
+	var body map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if body["success"] != false || body["msg"] != errno.ErrUserNotFound.Message {
+		t.Fatalf("unexpected body: %s", w.Body.String())
+	}
+	if _, ok := body["code"]; ok {
+		t.Fatalf("code key should be omitted: %s", w.Body.String())
+	}
+}
+
+func TestResponderNegotiation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := NewResponder(WithFormats(binding.MIMEJSON, binding.MIMEMSGPACK))
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	c.Request.Header.Set("Accept", binding.MIMEMSGPACK)
+	r.Send(c, nil, gin.H{"id": 1})
+	if !strings.HasPrefix(w.Header().Get("Content-Type"), "application/msgpack") {
+		t.Fatalf("expected msgpack, got %s", w.Header().Get("Content-Type"))
+	}
+
+	w = httptest.NewRecorder()
+	c, _ = gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Send(c, nil, gin.H{"id": 1})
+	if !strings.HasPrefix(w.Header().Get("Content-Type"), binding.MIMEJSON) {
+		t.Fatalf("expected json by default, got %s", w.Header().Get("Content-Type"))
+	}
+}
+
+func TestResponderLogBodyLimit(t *testing.T) {
+	r := NewResponder(WithLogBodyLimit(10))
+	got := r.logBodyString(Response{Message: strings.Repeat("错", 20)})
+	if !strings.HasSuffix(got, "...(truncated)") || len(got) > 10+len("...(truncated)") {
+		t.Fatalf("unexpected truncated log: %s", got)
+	}
+	// 默认不截断
+	if got := NewResponder().logBodyString(Response{Message: strings.Repeat("错", 1000)}); strings.HasSuffix(got, "...(truncated)") {
+		t.Fatalf("default responder should not truncate: %s", got)
+	}
+}
@@ -0,0 +1,216 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/Dev-Umb/go-pkg/errno"
+	"github.com/Dev-Umb/go-pkg/logger"
+
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+)
+
+// LastEventIDHeader 浏览器断线重连时携带的最后一个事件ID
+const LastEventIDHeader = "Last-Event-ID"
+
+const (
+	defaultHeartbeatInterval = 15 * time.Second
+	streamChunkSize          = 32 * 1024
+)
+
+// Event Server-Sent Events 事件
+// Data 和 Err 会被包装成与 SendResponse 相同的响应结构，并带上当前请求的 traceID
+type Event struct {
+	ID    string      // 事件ID，客户端重连时通过 Last-Event-ID 带回
+	Event string      // 事件类型，为空时客户端按 message 处理
+	Data  interface{} // 事件数据
+	Err   error       // 事件错误，非空时响应结构中的 code/message 取自该错误
+	Retry time.Duration
+}
+
+// SSEConfig SSE 推送配置
+type SSEConfig struct {
+	HeartbeatInterval time.Duration // 心跳间隔，小于等于0时不发送心跳
+	Retry             time.Duration // 建议客户端的重连间隔，随第一个事件下发
+}
+
+// SSEOption SSE 配置选项
+type SSEOption func(*SSEConfig)
+
+// WithHeartbeat 设置心跳间隔
+func WithHeartbeat(interval time.Duration) SSEOption {
+	return func(c *SSEConfig) {
+		c.HeartbeatInterval = interval
+	}
+}
+
+// WithRetry 设置客户端重连间隔
+func WithRetry(retry time.Duration) SSEOption {
+	return func(c *SSEConfig) {
+		c.Retry = retry
+	}
+}
+
+// LastEventID 获取客户端重连时携带的最后事件ID
+// 优先读取 Last-Event-ID 请求头，其次读取 lastEventId 查询参数（用于无法设置请求头的客户端）
+func LastEventID(c *gin.Context) string {
+	if id := c.GetHeader(LastEventIDHeader); id != "" {
+		return id
+	}
+	return c.Query("lastEventId")
+}
+
+// SendEvents 以 Server-Sent Events 方式持续推送 ch 中的事件
+// ch 关闭或客户端断开连接时返回，调用方应在客户端断开后停止向 ch 写入
+func SendEvents(c *gin.Context, ch <-chan Event, opts ...SSEOption) {
+	config := &SSEConfig{HeartbeatInterval: defaultHeartbeatInterval}
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	traceID := GetTraceIDFromGin(c)
+	header := c.Writer.Header()
+	header.Set("Content-Type", sse.ContentType)
+	header.Set("Cache-Control", "no-cache")
+	header.Set("Connection", "keep-alive")
+	header.Set("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	logger.Infof(c, "[%s] SSE stream started, last_event_id=%s", traceID, LastEventID(c))
+
+	var heartbeat <-chan time.Time
+	if config.HeartbeatInterval > 0 {
+		ticker := time.NewTicker(config.HeartbeatInterval)
+		defer ticker.Stop()
+		heartbeat = ticker.C
+	}
+
+	retry := config.Retry
+	count := 0
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			logger.Infof(c, "[%s] SSE client disconnected, sent=%d", traceID, count)
+			return
+		case <-heartbeat:
+			if _, err := io.WriteString(c.Writer, ": ping\n\n"); err != nil {
+				logger.Warnf(c, "[%s] SSE heartbeat failed: %v", traceID, err)
+				return
+			}
+			c.Writer.Flush()
+		case event, ok := <-ch:
+			if !ok {
+				logger.Infof(c, "[%s] SSE stream completed, sent=%d", traceID, count)
+				return
+			}
+			if event.Retry == 0 {
+				event.Retry = retry
+			}
+			if err := writeEvent(c, traceID, event); err != nil {
+				logger.Warnf(c, "[%s] SSE write event failed: %v", traceID, err)
+				return
+			}
+			retry = 0
+			count++
+		}
+	}
+}
+
+// writeEvent 编码并发送单个事件
+func writeEvent(c *gin.Context, traceID string, event Event) error {
+	code, _, message := errno.DecodeErr(event.Err)
+	data, err := json.Marshal(defaultResponder.envelope(Response{
+		Code:    code,
+		Message: message,
+		Data:    event.Data,
+		TraceID: traceID,
+	}))
+	if err != nil {
+		return err
+	}
+
+	if err := sse.Encode(c.Writer, sse.Event{
+		Id:    event.ID,
+		Event: event.Event,
+		Retry: uint(event.Retry / time.Millisecond),
+		Data:  data,
+	}); err != nil {
+		return err
+	}
+	c.Writer.Flush()
+	return nil
+}
+
+// SendFile 以附件形式发送本地文件，文件不可读时返回 FileDownloadError 响应
+func SendFile(c *gin.Context, path, filename string) {
+	info, err := os.Stat(path)
+	if err == nil && info.IsDir() {
+		err = fmt.Errorf("%s is a directory", path)
+	}
+	if err != nil {
+		logger.Errorf(c, "[%s] SendFile failed: %v", GetTraceIDFromGin(c), err)
+		SendResponse(c, errno.New(errno.FileDownloadError, err), nil)
+		return
+	}
+
+	if filename == "" {
+		filename = filepath.Base(path)
+	}
+	c.FileAttachment(path, filename)
+}
+
+// SendStream 以分块方式将 r 的内容作为附件发送，适用于导出等无法预知大小的下载
+// 读取第一块数据失败时返回 FileDownloadError 响应；已开始传输后失败只能中断连接并记录日志
+func SendStream(c *gin.Context, filename, contentType string, r io.Reader) {
+	traceID := GetTraceIDFromGin(c)
+	if closer, ok := r.(io.Closer); ok {
+		defer closer.Close()
+	}
+
+	buf := make([]byte, streamChunkSize)
+	n, err := io.ReadFull(r, buf)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		logger.Errorf(c, "[%s] SendStream read failed: %v", traceID, err)
+		SendResponse(c, errno.New(errno.FileDownloadError, err), nil)
+		return
+	}
+
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	header := c.Writer.Header()
+	header.Set("Content-Type", contentType)
+	if filename != "" {
+		header.Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+	}
+	c.Status(http.StatusOK)
+
+	written := int64(0)
+	for n > 0 {
+		if _, werr := c.Writer.Write(buf[:n]); werr != nil {
+			logger.Warnf(c, "[%s] SendStream write failed after %d bytes: %v", traceID, written, werr)
+			return
+		}
+		written += int64(n)
+		c.Writer.Flush()
+		if err != nil {
+			break
+		}
+		n, err = io.ReadFull(r, buf)
+		if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+			logger.Errorf(c, "[%s] SendStream read failed after %d bytes: %v", traceID, written, err)
+			return
+		}
+	}
+
+	logger.Infof(c, "[%s] SendStream completed: filename=%s, bytes=%d", traceID, filename, written)
+}
@@ -0,0 +1,71 @@
+package core
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Dev-Umb/go-pkg/errno"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestSendEvents(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/events", nil)
+	c.Request.Header.Set(LastEventIDHeader, "41")
+	c.Set(TraceIDKey, "trace-1")
+
+	if LastEventID(c) != "41" {
+		t.Fatalf("unexpected last event id: %s", LastEventID(c))
+	}
+
+	ch := make(chan Event, 2)
+	ch <- Event{ID: "42", Event: "notice", Data: "hello"}
+	ch <- Event{ID: "43", Err: errno.ErrTokenExpired}
+	close(ch)
+	SendEvents(c, ch, WithRetry(3*time.Second))
+
+	body := w.Body.String()
+	for _, want := range []string{
+		"id:42\nevent:notice\nretry:3000\n",
+		`"data":"hello","trace_id":"trace-1"`,
+		"id:43\n",
+		`"code":21002`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("expected %q in body:\n%s", want, body)
+		}
+	}
+	if strings.Count(body, "retry:") != 1 {
+		t.Fatalf("retry should only be sent once:\n%s", body)
+	}
+}
+
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) { return 0, errors.New("disk error") }
+
+func TestSendStream(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/export", nil)
+	SendStream(c, "export.csv", "text/csv", strings.NewReader("a,b\n1,2\n"))
+	if w.Body.String() != "a,b\n1,2\n" || !strings.Contains(w.Header().Get("Content-Disposition"), "export.csv") {
+		t.Fatalf("unexpected stream response: %q %v", w.Body.String(), w.Header())
+	}
+
+	w = httptest.NewRecorder()
+	c, _ = gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/export", nil)
+	SendStream(c, "export.csv", "text/csv", failingReader{})
+	if !strings.Contains(w.Body.String(), `"code":17002`) {
+		t.Fatalf("expected FileDownloadError envelope, got %s", w.Body.String())
+	}
+}
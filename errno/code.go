@@ -108,6 +108,15 @@ var (
 	ErrTokenUserMismatch = &Errno{Code: 21008, Message: "令牌与用户不匹配"}
 )
 
+// API密钥与请求签名错误码 (22000-22999)
+var (
+	ErrAPIKeyMissing     = &Errno{Code: 22001, Message: "缺少API密钥或签名"}
+	ErrAPIKeyInvalid     = &Errno{Code: 22002, Message: "无效的API密钥"}
+	ErrSignatureMismatch = &Errno{Code: 22003, Message: "请求签名不匹配"}
+	ErrTimestampExpired  = &Errno{Code: 22004, Message: "请求时间戳已过期"}
+	ErrNonceReplayed     = &Errno{Code: 22005, Message: "请求已被处理，请勿重放"}
+)
+
 // 网络与RPC错误码 (30000-30999)
 var (
 	ErrRPCConnection         = &Errno{Code: 30001, Message: "RPC连接失败"}
@@ -151,3 +160,26 @@ var ErrorMap = map[string]*Errno{
 	"no such host":              ErrNetworkUnavailable,
 	"connection reset by peer":  ErrNetworkUnavailable,
 }
+
+func init() {
+	Register(
+		OK,
+		InternalServerError, ConvertError, NotFoundError, TimeoutError, ErrBind,
+		ErrDatabase, ErrDatabaseConnect, ErrDatabaseQuery, ErrDatabaseInsert, ErrDatabaseUpdate, ErrDatabaseDelete, ErrDatabaseTransaction,
+		ErrRedis, ErrRedisConnect, ErrRedisSet, ErrRedisGet, ErrRedisDelete, ErrRedisExpire,
+		ErrUnauthorizedError, InvalidTokenError, ExpiredTokenError, GenerateJwtTokenError, TokenValidationError, PermissionDeniedError,
+		InvalidParamsError, MissingParamError, InvalidFormatError, ValueOutOfRangeError, BindRequestError,
+		ThirdPartyServiceError, APIGatewayError, ExternalAPIError,
+		RateLimitError, ConcurrencyLimitError, ServerBusyError,
+		FileUploadError, FileDownloadError, FileFormatError, FileSizeLimitError, FileSaveError,
+		AuthCodeError, AuthCodeExpiredError, AuthCodeGenerateError, AuthCodeSendError,
+		PasswordError, PasswordFormatError, PasswordResetError, PasswordNotMatchError,
+		SendEmailError,
+		ErrUserNotFound, ErrUserAlreadyExist, ErrUserCreateFailed, ErrUserUpdateFailed, ErrUserDeleteFailed, ErrUserIDInvalid,
+		ErrUserLocked, ErrUserDisabled, ErrUserPhoneInvalid, ErrUserEmailInvalid, ErrUserAvatarInvalid, ErrUserLoginFailed, ErrUserLogoutFailed,
+		ErrTokenInvalid, ErrTokenExpired, ErrTokenRevoked, ErrTokenMalformed, ErrTokenMissing, ErrTokenGenerate, ErrTokenValidate, ErrTokenUserMismatch,
+		ErrAPIKeyMissing, ErrAPIKeyInvalid, ErrSignatureMismatch, ErrTimestampExpired, ErrNonceReplayed,
+		ErrRPCConnection, ErrRPCTimeout, ErrRPCInvalidResponse, ErrRPCServiceUnavailable, ErrNetworkUnavailable, ErrNetworkTimeout, ErrDNSResolution,
+		ErrConfigNotFound, ErrConfigInvalid, ErrConfigParse, ErrConfigLoad,
+	)
+}
@@ -2,6 +2,7 @@ package errno
 
 import (
 	"fmt"
+	"sync"
 )
 
 // Errno ...
@@ -14,11 +15,39 @@ func (err Errno) Error() string {
 	return err.Message
 }
 
+var (
+	registry   = make(map[int]*Errno)
+	registryMu sync.RWMutex
+)
+
+// Register 注册错误码，便于按错误码反查到同一个 *Errno 实例
+// 业务服务自定义的错误码需要在启动时注册，否则跨服务传递后只能还原为 *Err
+func Register(errnos ...*Errno) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	for _, e := range errnos {
+		if e == nil {
+			continue
+		}
+		registry[e.Code] = e
+	}
+}
+
+// Lookup 根据错误码查找已注册的 *Errno
+func Lookup(code int) (*Errno, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	e, ok := registry[code]
+	return e, ok
+}
+
 // Err represents an error
 type Err struct {
 	Code    int
 	Message string
 	Err     string
+	// Data 错误附带的数据，例如参数校验失败的字段明细，会作为响应的 data 返回
+	Data interface{}
 }
 
 // New ...
@@ -26,6 +55,12 @@ func New(errno *Errno, err error) *Err {
 	return &Err{Code: errno.Code, Message: err.Error(), Err: err.Error()}
 }
 
+// WithData 设置错误附带的数据
+func (err *Err) WithData(data interface{}) *Err {
+	err.Data = data
+	return err
+}
+
 // Add ...
 func (err *Err) Add(message string) error {
 	err.Message += " " + message
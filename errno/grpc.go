@@ -0,0 +1,186 @@
+package errno
+
+import (
+	"context"
+	"strconv"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// GRPCErrorDomain 写入 status details 中 ErrorInfo.Domain 的值，用于识别 errno 错误
+const GRPCErrorDomain = "errno"
+
+// grpcCodeMetadataKey ErrorInfo.Metadata 中存放业务错误码的键
+const grpcCodeMetadataKey = "code"
+
+// 精确匹配的错误码映射，优先级高于按区间映射
+var grpcCodeMap = map[int]codes.Code{
+	OK.Code:                       codes.OK,
+	NotFoundError.Code:            codes.NotFound,
+	TimeoutError.Code:             codes.DeadlineExceeded,
+	PermissionDeniedError.Code:    codes.PermissionDenied,
+	RateLimitError.Code:           codes.ResourceExhausted,
+	ConcurrencyLimitError.Code:    codes.ResourceExhausted,
+	ServerBusyError.Code:          codes.Unavailable,
+	ErrUserNotFound.Code:          codes.NotFound,
+	ErrUserAlreadyExist.Code:      codes.AlreadyExists,
+	ErrRPCTimeout.Code:            codes.DeadlineExceeded,
+	ErrNetworkTimeout.Code:        codes.DeadlineExceeded,
+	ErrRPCServiceUnavailable.Code: codes.Unavailable,
+	ErrNetworkUnavailable.Code:    codes.Unavailable,
+	ErrConfigNotFound.Code:        codes.NotFound,
+	FileSizeLimitError.Code:       codes.ResourceExhausted,
+}
+
+// GRPCCode 将业务错误码映射为 gRPC 状态码
+func GRPCCode(code int) codes.Code {
+	if c, ok := grpcCodeMap[code]; ok {
+		return c
+	}
+
+	switch {
+	case code >= 10000 && code < 13000:
+		return codes.Internal
+	case code >= 13000 && code < 14000:
+		return codes.Unauthenticated
+	case code >= 14000 && code < 15000:
+		return codes.InvalidArgument
+	case code >= 15000 && code < 16000:
+		return codes.Unavailable
+	case code >= 16000 && code < 17000:
+		return codes.ResourceExhausted
+	case code >= 17000 && code < 18000:
+		return codes.Internal
+	case code >= 18000 && code < 20000:
+		return codes.InvalidArgument
+	case code >= 21000 && code < 23000:
+		return codes.Unauthenticated
+	case code >= 30000 && code < 31000:
+		return codes.Unavailable
+	case code >= 40000 && code < 41000:
+		return codes.FailedPrecondition
+	default:
+		return codes.Unknown
+	}
+}
+
+// 没有 errno 详情的 gRPC 错误（如传输层错误）按状态码还原为通用错误码
+var fallbackErrnoMap = map[codes.Code]*Errno{
+	codes.Canceled:          TimeoutError,
+	codes.DeadlineExceeded:  ErrRPCTimeout,
+	codes.Unavailable:       ErrRPCServiceUnavailable,
+	codes.Unauthenticated:   ErrUnauthorizedError,
+	codes.PermissionDenied:  PermissionDeniedError,
+	codes.NotFound:          NotFoundError,
+	codes.InvalidArgument:   InvalidParamsError,
+	codes.ResourceExhausted: RateLimitError,
+}
+
+// ToGRPCStatus 将错误转换为 gRPC status
+// 业务错误码会以 ErrorInfo 的形式写入 status details，便于调用方还原
+func ToGRPCStatus(err error) *status.Status {
+	if err == nil {
+		return status.New(codes.OK, OK.Message)
+	}
+	// 已经是 gRPC status 的错误原样返回
+	if st, ok := status.FromError(err); ok {
+		return st
+	}
+
+	code, _, message := DecodeErr(err)
+	st := status.New(GRPCCode(code), message)
+	detailed, detailErr := st.WithDetails(&errdetails.ErrorInfo{
+		Reason:   "ERRNO_" + strconv.Itoa(code),
+		Domain:   GRPCErrorDomain,
+		Metadata: map[string]string{grpcCodeMetadataKey: strconv.Itoa(code)},
+	})
+	if detailErr != nil {
+		return st
+	}
+	return detailed
+}
+
+// FromGRPCStatus 将 gRPC status 还原为 errno 错误
+// 已注册且消息未被修改的错误码返回同一个 *Errno，其余返回 *Err
+func FromGRPCStatus(st *status.Status) error {
+	if st == nil || st.Code() == codes.OK {
+		return nil
+	}
+
+	for _, detail := range st.Details() {
+		info, ok := detail.(*errdetails.ErrorInfo)
+		if !ok || info.GetDomain() != GRPCErrorDomain {
+			continue
+		}
+		code, err := strconv.Atoi(info.GetMetadata()[grpcCodeMetadataKey])
+		if err != nil {
+			continue
+		}
+		if e, ok := Lookup(code); ok && e.Message == st.Message() {
+			return e
+		}
+		return &Err{Code: code, Message: st.Message(), Err: st.Message()}
+	}
+
+	fallback, ok := fallbackErrnoMap[st.Code()]
+	if !ok {
+		fallback = InternalServerError
+	}
+	return &Err{Code: fallback.Code, Message: st.Message(), Err: st.Message()}
+}
+
+// FromGRPCError 将 gRPC 调用返回的错误还原为 errno 错误
+// 非 gRPC status 的错误原样返回
+func FromGRPCError(err error) error {
+	if err == nil {
+		return nil
+	}
+	st, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+	return FromGRPCStatus(st)
+}
+
+// UnaryServerInterceptor 将处理函数返回的 errno 错误转换为 gRPC status
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		resp, err := handler(ctx, req)
+		if err != nil {
+			return resp, ToGRPCStatus(err).Err()
+		}
+		return resp, nil
+	}
+}
+
+// StreamServerInterceptor 流式接口的 errno 错误转换
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := handler(srv, ss); err != nil {
+			return ToGRPCStatus(err).Err()
+		}
+		return nil
+	}
+}
+
+// UnaryClientInterceptor 将远端返回的 gRPC status 还原为 errno 错误
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return FromGRPCError(invoker(ctx, method, req, reply, cc, opts...))
+	}
+}
+
+// StreamClientInterceptor 流式调用建立连接时的错误还原
+// 流中收发消息的错误需调用方自行使用 FromGRPCError 转换
+func StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		cs, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			return nil, FromGRPCError(err)
+		}
+		return cs, nil
+	}
+}
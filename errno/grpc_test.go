@@ -0,0 +1,40 @@
+package errno
+
+import (
+	"errors"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGRPCStatusRoundTrip(t *testing.T) {
+	st := ToGRPCStatus(ErrUserNotFound)
+	if st.Code() != codes.NotFound {
+		t.Fatalf("expected NotFound, got %v", st.Code())
+	}
+
+	err := FromGRPCError(st.Err())
+	if err != ErrUserNotFound {
+		t.Fatalf("expected ErrUserNotFound, got %#v", err)
+	}
+}
+
+func TestGRPCStatusWrappedErr(t *testing.T) {
+	st := ToGRPCStatus(New(ErrTokenExpired, errors.New("token is expired")))
+	if st.Code() != codes.Unauthenticated {
+		t.Fatalf("expected Unauthenticated, got %v", st.Code())
+	}
+
+	code, _, message := DecodeErr(FromGRPCStatus(st))
+	if code != ErrTokenExpired.Code || message != "token is expired" {
+		t.Fatalf("unexpected decode result: %d %s", code, message)
+	}
+}
+
+func TestGRPCStatusWithoutDetails(t *testing.T) {
+	code, _, _ := DecodeErr(FromGRPCError(status.Error(codes.Unavailable, "connection refused")))
+	if code != ErrRPCServiceUnavailable.Code {
+		t.Fatalf("expected %d, got %d", ErrRPCServiceUnavailable.Code, code)
+	}
+}
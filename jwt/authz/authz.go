@@ -0,0 +1,189 @@
+package authz
+
+import (
+	"context"
+	"sync"
+
+	"github.com/Dev-Umb/go-pkg/core"
+	"github.com/Dev-Umb/go-pkg/errno"
+	"github.com/Dev-Umb/go-pkg/jwt"
+	"github.com/Dev-Umb/go-pkg/logger"
+	"github.com/gin-gonic/gin"
+)
+
+// Principal 授权主体，通常来自token中的声明
+type Principal struct {
+	UserId string
+	Roles  []string
+	Scopes []string
+}
+
+// PrincipalFunc 从请求中提取授权主体，未认证时返回 false
+type PrincipalFunc func(c *gin.Context) (*Principal, bool)
+
+// Policy 授权策略
+type Policy interface {
+	// HasRole 判断主体是否拥有角色（含继承得到的角色）
+	HasRole(ctx context.Context, p *Principal, role string) bool
+	// HasScope 判断主体是否拥有权限范围
+	HasScope(ctx context.Context, p *Principal, scope string) bool
+}
+
+// ClaimsPolicy 只根据token中携带的角色和权限范围精确匹配
+type ClaimsPolicy struct{}
+
+// HasRole 判断token中是否携带角色
+func (ClaimsPolicy) HasRole(ctx context.Context, p *Principal, role string) bool {
+	return contains(p.Roles, role)
+}
+
+// HasScope 判断token中是否携带权限范围
+func (ClaimsPolicy) HasScope(ctx context.Context, p *Principal, scope string) bool {
+	return contains(p.Scopes, scope)
+}
+
+// Authorizer 授权器
+type Authorizer struct {
+	mu        sync.RWMutex // 保护 policy，UsePolicy 可能与请求并发
+	policy    Policy
+	principal PrincipalFunc
+}
+
+// Option 授权器配置选项
+type Option func(*Authorizer)
+
+// WithPolicy 设置授权策略，默认为 ClaimsPolicy
+func WithPolicy(policy Policy) Option {
+	return func(a *Authorizer) {
+		a.policy = policy
+	}
+}
+
+// WithPrincipalFunc 设置授权主体提取函数，用于使用自定义声明的服务
+func WithPrincipalFunc(fn PrincipalFunc) Option {
+	return func(a *Authorizer) {
+		a.principal = fn
+	}
+}
+
+// New 创建授权器
+func New(opts ...Option) *Authorizer {
+	a := &Authorizer{policy: ClaimsPolicy{}, principal: PrincipalFromClaims}
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a
+}
+
+var (
+	defaultAuthorizer   = New()
+	defaultAuthorizerMu sync.RWMutex
+)
+
+// Default 返回包级别函数使用的授权器
+func Default() *Authorizer {
+	defaultAuthorizerMu.RLock()
+	defer defaultAuthorizerMu.RUnlock()
+	return defaultAuthorizer
+}
+
+// SetDefault 设置包级别函数使用的授权器，只影响之后创建的中间件
+func SetDefault(a *Authorizer) {
+	if a != nil {
+		defaultAuthorizerMu.Lock()
+		defaultAuthorizer = a
+		defaultAuthorizerMu.Unlock()
+	}
+}
+
+// UsePolicy 设置默认授权器的授权策略，对已创建的中间件同样生效
+func UsePolicy(policy Policy) {
+	Default().SetPolicy(policy)
+}
+
+// SetPolicy 替换授权策略，可与请求并发调用
+func (a *Authorizer) SetPolicy(policy Policy) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.policy = policy
+}
+
+// currentPolicy 返回当前的授权策略
+func (a *Authorizer) currentPolicy() Policy {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.policy
+}
+
+// PrincipalFromClaims 从 jwt.AuthMiddleware 写入的声明中提取授权主体
+func PrincipalFromClaims(c *gin.Context) (*Principal, bool) {
+	user, ok := jwt.CurrentUser(c)
+	if !ok {
+		return nil, false
+	}
+	return &Principal{UserId: user.UserId, Roles: user.Roles, Scopes: user.Scopes}, true
+}
+
+// RequireRoles 使用默认授权器，要求拥有任意一个角色
+func RequireRoles(roles ...string) gin.HandlerFunc {
+	return Default().RequireRoles(roles...)
+}
+
+// RequireScopes 使用默认授权器，要求拥有全部权限范围
+func RequireScopes(scopes ...string) gin.HandlerFunc {
+	return Default().RequireScopes(scopes...)
+}
+
+// RequireRoles 要求拥有任意一个角色，需在 jwt.AuthMiddleware 之后使用
+func (a *Authorizer) RequireRoles(roles ...string) gin.HandlerFunc {
+	return a.require(func(ctx context.Context, p *Principal) bool {
+		policy := a.currentPolicy()
+		for _, role := range roles {
+			if policy.HasRole(ctx, p, role) {
+				return true
+			}
+		}
+		return len(roles) == 0
+	})
+}
+
+// RequireScopes 要求拥有全部权限范围，需在 jwt.AuthMiddleware 之后使用
+func (a *Authorizer) RequireScopes(scopes ...string) gin.HandlerFunc {
+	return a.require(func(ctx context.Context, p *Principal) bool {
+		policy := a.currentPolicy()
+		for _, scope := range scopes {
+			if !policy.HasScope(ctx, p, scope) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// require 未认证返回 ErrTokenMissing，未授权返回 PermissionDeniedError
+func (a *Authorizer) require(allow func(ctx context.Context, p *Principal) bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		p, ok := a.principal(c)
+		if !ok {
+			core.SendResponse(c, errno.ErrTokenMissing, nil)
+			c.Abort()
+			return
+		}
+		if !allow(c.Request.Context(), p) {
+			logger.Warnf(c, "permission denied: user=%s path=%s", p.UserId, c.FullPath())
+			core.SendResponse(c, errno.PermissionDeniedError, nil)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+func contains(values []string, target string) bool {
+	for _, v := range values {
+		if v == target {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,106 @@
+package authz
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Dev-Umb/go-pkg/errno"
+	"github.com/Dev-Umb/go-pkg/jwt"
+	"github.com/gin-gonic/gin"
+)
+
+func doRequest(r *gin.Engine, token string) float64 {
+	req := httptest.NewRequest(http.MethodGet, "/orders", nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	var body map[string]interface{}
+	_ = json.Unmarshal(w.Body.Bytes(), &body)
+	code, _ := body["code"].(float64)
+	return code
+}
+
+func TestRequireRolesAndScopes(t *testing.T) {
+	jwt.InitJwtSecret("test-secret")
+	rbac := NewRBAC(
+		Role{Name: "viewer", Permissions: []string{"order:read"}},
+		Role{Name: "editor", Permissions: []string{"order:write"}, Parents: []string{"viewer"}},
+		Role{Name: "admin", Permissions: []string{"*"}, Parents: []string{"editor"}},
+	)
+	a := New(WithPolicy(rbac))
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/orders", jwt.AuthMiddleware(jwt.AuthOptions{}), a.RequireRoles("viewer"), a.RequireScopes("order:write"),
+		func(c *gin.Context) { c.JSON(http.StatusOK, gin.H{"code": 0}) })
+
+	editor, _ := jwt.GenerateToken(jwt.UserInfo{UserId: "u1", Roles: []string{"editor"}})
+	viewer, _ := jwt.GenerateToken(jwt.UserInfo{UserId: "u2", Roles: []string{"viewer"}})
+	scoped, _ := jwt.GenerateToken(jwt.UserInfo{UserId: "u3", Roles: []string{"viewer"}, Scopes: []string{"order:*"}})
+
+	if code := doRequest(r, editor); code != 0 {
+		t.Fatalf("editor should inherit viewer, got %v", code)
+	}
+	if code := doRequest(r, viewer); code != float64(errno.PermissionDeniedError.Code) {
+		t.Fatalf("viewer should be denied, got %v", code)
+	}
+	if code := doRequest(r, scoped); code != 0 {
+		t.Fatalf("token scope should grant access, got %v", code)
+	}
+	if code := doRequest(r, ""); code != float64(errno.ErrTokenMissing.Code) {
+		t.Fatalf("expected missing token, got %v", code)
+	}
+}
+
+func TestRBACInheritanceCycle(t *testing.T) {
+	rbac := NewRBAC()
+	rbac.Inherit("a", "b")
+	rbac.Inherit("b", "a")
+	rbac.Grant("b", "report:read")
+
+	p := &Principal{Roles: []string{"a"}}
+	if !rbac.HasRole(context.Background(), p, "b") || !rbac.HasScope(context.Background(), p, "report:read") {
+		t.Fatalf("expected inherited role and permission")
+	}
+	if rbac.HasScope(context.Background(), p, "report:write") {
+		t.Fatalf("unexpected permission")
+	}
+}
+
+func TestUsePolicyConcurrentWithRequests(t *testing.T) {
+	jwt.InitJwtSecret("test-secret")
+	defer UsePolicy(ClaimsPolicy{})
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/orders", jwt.AuthMiddleware(jwt.AuthOptions{}), RequireRoles("editor"),
+		func(c *gin.Context) { c.JSON(http.StatusOK, gin.H{"code": 0}) })
+	viewer, _ := jwt.GenerateToken(jwt.UserInfo{UserId: "u1", Roles: []string{"viewer"}})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			UsePolicy(ClaimsPolicy{})
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		doRequest(r, viewer)
+	}
+	<-done
+
+	// 已创建的中间件使用新的策略
+	UsePolicy(NewRBAC(Role{Name: "editor", Parents: []string{"viewer"}}, Role{Name: "viewer"}))
+	if code := doRequest(r, viewer); code != float64(errno.PermissionDeniedError.Code) {
+		t.Fatalf("viewer should be denied, got %v", code)
+	}
+	UsePolicy(NewRBAC(Role{Name: "viewer", Parents: []string{"editor"}}, Role{Name: "editor"}))
+	if code := doRequest(r, viewer); code != 0 {
+		t.Fatalf("viewer should inherit editor after policy change, got %v", code)
+	}
+}
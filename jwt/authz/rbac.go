@@ -0,0 +1,118 @@
+package authz
+
+import (
+	"context"
+	"strings"
+	"sync"
+)
+
+// Role RBAC角色定义
+type Role struct {
+	Name        string
+	Permissions []string // 权限范围，支持 "*" 和 "order:*" 形式的通配
+	Parents     []string // 继承的角色，拥有父角色的全部角色和权限
+}
+
+// RBAC 基于角色的访问控制策略
+// 主体拥有的角色包括token中的角色及其继承的全部角色，
+// 拥有的权限范围包括token中的权限范围和这些角色的权限
+type RBAC struct {
+	mu    sync.RWMutex
+	roles map[string]Role
+}
+
+// NewRBAC 创建RBAC策略
+func NewRBAC(roles ...Role) *RBAC {
+	r := &RBAC{roles: make(map[string]Role)}
+	for _, role := range roles {
+		r.AddRole(role)
+	}
+	return r
+}
+
+// AddRole 添加角色，名称相同时覆盖
+func (r *RBAC) AddRole(role Role) {
+	r.mu.Lock()
+	r.roles[role.Name] = role
+	r.mu.Unlock()
+}
+
+// Inherit 设置 role 继承 parents，例如 admin 继承 editor
+func (r *RBAC) Inherit(role string, parents ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	def := r.roles[role]
+	def.Name = role
+	def.Parents = append(def.Parents, parents...)
+	r.roles[role] = def
+}
+
+// Grant 为角色授予权限范围
+func (r *RBAC) Grant(role string, permissions ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	def := r.roles[role]
+	def.Name = role
+	def.Permissions = append(def.Permissions, permissions...)
+	r.roles[role] = def
+}
+
+// HasRole 判断主体是否直接或通过继承拥有角色
+func (r *RBAC) HasRole(ctx context.Context, p *Principal, role string) bool {
+	_, ok := r.expand(p.Roles)[role]
+	return ok
+}
+
+// HasScope 判断主体是否拥有权限范围
+func (r *RBAC) HasScope(ctx context.Context, p *Principal, scope string) bool {
+	for _, s := range p.Scopes {
+		if matchScope(s, scope) {
+			return true
+		}
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for name := range r.expandLocked(p.Roles) {
+		for _, perm := range r.roles[name].Permissions {
+			if matchScope(perm, scope) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// expand 展开角色继承
+func (r *RBAC) expand(roles []string) map[string]struct{} {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.expandLocked(roles)
+}
+
+// expandLocked 展开角色继承，调用方需持有读锁；循环继承只展开一次
+func (r *RBAC) expandLocked(roles []string) map[string]struct{} {
+	result := make(map[string]struct{}, len(roles))
+	queue := append([]string(nil), roles...)
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		if _, ok := result[name]; ok {
+			continue
+		}
+		result[name] = struct{}{}
+		queue = append(queue, r.roles[name].Parents...)
+	}
+	return result
+}
+
+// matchScope 判断权限 granted 是否覆盖 required
+func matchScope(granted, required string) bool {
+	if granted == "*" || granted == required {
+		return true
+	}
+	if strings.HasSuffix(granted, ":*") {
+		return strings.HasPrefix(required, granted[:len(granted)-1])
+	}
+	return false
+}
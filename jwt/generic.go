@@ -0,0 +1,142 @@
+package jwt
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+
+	"github.com/Dev-Umb/go-pkg/errno"
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// ClaimsPtr 自定义声明约束，T 为嵌入 jwt.RegisteredClaims 的结构体
+//
+//	type AppClaims struct {
+//		UserId   string   `json:"user_id"`
+//		TenantId string   `json:"tenant_id"`
+//		Roles    []string `json:"roles"`
+//		jwt.RegisteredClaims
+//	}
+//
+//	token, err := jwt.Generate(&AppClaims{UserId: "u1", Roles: []string{"admin"}})
+//	claims, err := jwt.Parse[AppClaims](token)
+type ClaimsPtr[T any] interface {
+	*T
+	jwt.Claims
+}
+
+// Generate 使用默认管理器签发自定义声明的token
+func Generate[T any, PT ClaimsPtr[T]](claims PT) (string, error) {
+	return GenerateWith[T, PT](defaultManager, claims)
+}
+
+// GenerateWith 使用指定管理器签发自定义声明的token
+// 未设置的 iss、aud、jti、iat、nbf、exp 由管理器填充，sub 默认取 CustomClaims 的 UserId
+func GenerateWith[T any, PT ClaimsPtr[T]](m *Manager, claims PT) (string, error) {
+	rc, err := registeredClaimsOf(claims)
+	if err != nil {
+		return "", err
+	}
+	s, err := m.currentSigner()
+	if err != nil {
+		return "", err
+	}
+
+	subject := ""
+	if c, ok := any(claims).(*CustomClaims); ok {
+		subject = c.UserId
+	}
+	ttl, _, _ := m.ttls()
+	m.fillRegistered(rc, subject, ttl)
+	return s.Sign(claims)
+}
+
+// Parse 使用默认管理器解析自定义声明的token
+func Parse[T any, PT ClaimsPtr[T]](tokenString string) (PT, error) {
+	return ParseWith[T, PT](defaultManager, tokenString)
+}
+
+// ParseWith 使用指定管理器解析自定义声明的token
+// 拒绝刷新令牌；设置了撤销存储时，已撤销的token返回 errno.ErrTokenRevoked
+func ParseWith[T any, PT ClaimsPtr[T]](m *Manager, tokenString string) (PT, error) {
+	claims := PT(new(T))
+	if err := m.parse(tokenString, claims); err != nil {
+		return nil, err
+	}
+
+	// 令牌类型和令牌族从载荷读取，不依赖 T 是否声明了这两个字段
+	marker, err := tokenMarkerOf(tokenString)
+	if err != nil {
+		return nil, err
+	}
+	if marker.TokenType == TokenTypeRefresh {
+		return nil, errno.ErrTokenInvalid
+	}
+	rc, _ := registeredClaimsOf(claims)
+	if err := m.checkRevoked(context.Background(), rc.ID, marker.FamilyID); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+// tokenMarker 载荷中的令牌类型和令牌族，与 CustomClaims 的字段一致
+type tokenMarker struct {
+	TokenType string `json:"token_type"`
+	FamilyID  string `json:"fid"`
+}
+
+// tokenMarkerOf 读取已验证签名的token载荷中的令牌类型和令牌族
+func tokenMarkerOf(tokenString string) (tokenMarker, error) {
+	var marker tokenMarker
+	parts := strings.Split(tokenString, ".")
+	if len(parts) != 3 {
+		return marker, errno.ErrTokenInvalid
+	}
+	payload, err := jwt.DecodeSegment(parts[1])
+	if err != nil {
+		return marker, errno.ErrTokenInvalid
+	}
+	if err := json.Unmarshal(payload, &marker); err != nil {
+		return marker, errno.ErrTokenInvalid
+	}
+	return marker, nil
+}
+
+var registeredClaimsType = reflect.TypeOf(jwt.RegisteredClaims{})
+
+// registeredClaimsOf 查找声明结构体中嵌入的 jwt.RegisteredClaims
+func registeredClaimsOf(claims interface{}) (*jwt.RegisteredClaims, error) {
+	if rc, ok := claims.(*jwt.RegisteredClaims); ok {
+		return rc, nil
+	}
+	v := reflect.ValueOf(claims)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return nil, errors.New("声明必须是结构体指针")
+	}
+	if rc := findRegisteredClaims(v.Elem()); rc != nil {
+		return rc, nil
+	}
+	return nil, errors.New("声明结构体未嵌入 jwt.RegisteredClaims")
+}
+
+// findRegisteredClaims 在匿名字段中递归查找 jwt.RegisteredClaims
+func findRegisteredClaims(v reflect.Value) *jwt.RegisteredClaims {
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		if !field.Anonymous {
+			continue
+		}
+		fv := v.Field(i)
+		if field.Type == registeredClaimsType {
+			return fv.Addr().Interface().(*jwt.RegisteredClaims)
+		}
+		if fv.Kind() == reflect.Struct {
+			if rc := findRegisteredClaims(fv); rc != nil {
+				return rc
+			}
+		}
+	}
+	return nil
+}
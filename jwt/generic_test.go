@@ -0,0 +1,72 @@
+package jwt
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Dev-Umb/go-pkg/errno"
+	"github.com/golang-jwt/jwt/v4"
+)
+
+type appClaims struct {
+	TenantId string   `json:"tenant_id"`
+	Roles    []string `json:"roles"`
+	jwt.RegisteredClaims
+}
+
+func TestGenerateParseCustomClaims(t *testing.T) {
+	m := NewManager(WithSecret("test-secret"), WithIssuer("auth"))
+	token, err := GenerateWith(m, &appClaims{
+		TenantId:         "t1",
+		Roles:            []string{"admin"},
+		RegisteredClaims: jwt.RegisteredClaims{Subject: "u1"},
+	})
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+
+	claims, err := ParseWith[appClaims](m, token)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if claims.TenantId != "t1" || len(claims.Roles) != 1 || claims.Subject != "u1" {
+		t.Fatalf("unexpected claims: %+v", claims)
+	}
+	if claims.Issuer != "auth" || claims.ID == "" || claims.ExpiresAt == nil {
+		t.Fatalf("registered claims not filled: %+v", claims.RegisteredClaims)
+	}
+
+	// 默认类型 CustomClaims 与 ParseToken 结果一致
+	user, err := ParseWith[CustomClaims](m, token)
+	if err != nil {
+		t.Fatalf("parse default claims: %v", err)
+	}
+	if user.Subject != "u1" {
+		t.Fatalf("unexpected subject: %s", user.Subject)
+	}
+}
+
+func TestGenerateRequiresRegisteredClaims(t *testing.T) {
+	type noRegistered struct{ jwt.MapClaims }
+	if _, err := registeredClaimsOf(&noRegistered{}); err == nil {
+		t.Fatalf("expected error for claims without RegisteredClaims")
+	}
+}
+
+func TestParseWithRejectsRefreshToken(t *testing.T) {
+	m := NewManager(WithSecret("test-secret"))
+	pair, err := m.GenerateTokenPair(UserInfo{UserId: "u1"})
+	if err != nil {
+		t.Fatalf("generate pair: %v", err)
+	}
+	if _, err := ParseWith[appClaims](m, pair.AccessToken); err != nil {
+		t.Fatalf("access token should be valid: %v", err)
+	}
+	// appClaims 没有声明 token_type，仍需识别刷新令牌
+	if _, err := ParseWith[appClaims](m, pair.RefreshToken); !errors.Is(err, errno.ErrTokenInvalid) {
+		t.Fatalf("refresh token accepted as access token, err = %v", err)
+	}
+	if _, err := ParseWith[CustomClaims](m, pair.RefreshToken); !errors.Is(err, errno.ErrTokenInvalid) {
+		t.Fatalf("refresh token accepted as access token, err = %v", err)
+	}
+}
@@ -0,0 +1,266 @@
+package jwt
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Dev-Umb/go-pkg/errno"
+	"github.com/Dev-Umb/go-pkg/logger"
+
+	"github.com/golang-jwt/jwt/v4"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// AuthorizationMetadataKey gRPC元数据中携带token的键，值为 "Bearer <token>"
+const AuthorizationMetadataKey = "authorization"
+
+// ServiceRole 服务间调用token携带的角色
+const ServiceRole = "service"
+
+// GRPCAuthOptions gRPC认证拦截器配置
+type GRPCAuthOptions struct {
+	// Manager 校验token使用的管理器，为空时使用默认管理器
+	Manager *Manager
+	// SkipMethods 免认证的方法全名，如 /grpc.health.v1.Health/Check
+	SkipMethods []string
+	// Optional 可选认证，未携带token时匿名放行；携带了无效token仍然拒绝
+	Optional bool
+}
+
+// authenticate 从元数据中提取并校验token，成功后将声明和token写入context
+// 失败时返回 codes.Unauthenticated，details 中携带对应的 errno 错误码
+func (opts GRPCAuthOptions) authenticate(ctx context.Context, fullMethod string) (context.Context, error) {
+	for _, method := range opts.SkipMethods {
+		if method == fullMethod {
+			return ctx, nil
+		}
+	}
+
+	tokenString := tokenFromMetadata(ctx)
+	if tokenString == "" {
+		if opts.Optional {
+			return ctx, nil
+		}
+		return nil, errno.ToGRPCStatus(errno.ErrTokenMissing).Err()
+	}
+
+	m := opts.Manager
+	if m == nil {
+		m = defaultManager
+	}
+	claims, err := m.ParseToken(tokenString)
+	if err != nil {
+		logger.Warnf(ctx, "grpc jwt auth failed: method=%s: %v", fullMethod, err)
+		st := errno.ToGRPCStatus(TokenErrno(err))
+		if st.Code() != codes.Unauthenticated {
+			// 撤销存储不可用等内部错误也按未认证处理，避免放行
+			return nil, status.Error(codes.Unauthenticated, st.Message())
+		}
+		return nil, st.Err()
+	}
+	return WithToken(WithClaims(ctx, claims), tokenString), nil
+}
+
+// tokenFromMetadata 从入站元数据中提取token
+func tokenFromMetadata(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	values := md.Get(AuthorizationMetadataKey)
+	if len(values) == 0 {
+		return ""
+	}
+	return ExtractBearerToken(values[0])
+}
+
+// UnaryServerInterceptor gRPC服务端认证拦截器，处理函数可通过 UserFromContext 获取当前用户
+func UnaryServerInterceptor(opts GRPCAuthOptions) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := opts.authenticate(ctx, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor gRPC流式接口认证拦截器
+func StreamServerInterceptor(opts GRPCAuthOptions) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := opts.authenticate(ss.Context(), info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &authServerStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// authServerStream 替换流的context
+type authServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authServerStream) Context() context.Context {
+	return s.ctx
+}
+
+// TokenSource 为出站gRPC调用提供token，返回空字符串表示不携带token
+type TokenSource func(ctx context.Context) (string, error)
+
+// ForwardToken 转发当前请求的token（由 AuthMiddleware 或 UnaryServerInterceptor 写入context）
+func ForwardToken() TokenSource {
+	return func(ctx context.Context) (string, error) {
+		tokenString, _ := TokenFromContext(ctx)
+		return tokenString, nil
+	}
+}
+
+// DefaultServiceTokenTTL 服务间调用token的默认有效期，见 WithServiceTokenTTL
+const DefaultServiceTokenTTL = 10 * time.Minute
+
+// ServiceToken 签发服务间调用token并缓存，过期前一分钟（有效期较短时为一半有效期）重新签发
+// token的 UserId 为 "service:<serviceName>"，角色为 ServiceRole，有效期见 WithServiceTokenTTL；m 为空时使用默认管理器
+func ServiceToken(m *Manager, serviceName string) TokenSource {
+	var (
+		mu        sync.Mutex
+		cached    string
+		expiresAt time.Time
+	)
+	return func(ctx context.Context) (string, error) {
+		mgr := m
+		if mgr == nil {
+			mgr = defaultManager
+		}
+
+		ttl := mgr.serviceTokenTTL()
+		renewBefore := time.Minute
+		if renewBefore > ttl/2 {
+			renewBefore = ttl / 2
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		now := mgr.now()
+		if cached != "" && now.Add(renewBefore).Before(expiresAt) {
+			return cached, nil
+		}
+
+		exp := now.Add(ttl)
+		tokenString, err := GenerateWith(mgr, &CustomClaims{
+			UserInfo: UserInfo{
+				UserId:   "service:" + serviceName,
+				UserName: serviceName,
+				Roles:    []string{ServiceRole},
+			},
+			RegisteredClaims: jwt.RegisteredClaims{
+				IssuedAt:  jwt.NewNumericDate(now),
+				NotBefore: jwt.NewNumericDate(now),
+				ExpiresAt: jwt.NewNumericDate(exp),
+			},
+		})
+		if err != nil {
+			logger.Errorf(ctx, "签发服务间调用token失败: %v", err)
+			return "", err
+		}
+		cached, expiresAt = tokenString, exp
+		return cached, nil
+	}
+}
+
+// firstToken 依次尝试token来源，返回第一个非空token
+func firstToken(ctx context.Context, sources []TokenSource) (string, error) {
+	for _, source := range sources {
+		tokenString, err := source(ctx)
+		if err != nil {
+			return "", status.Errorf(codes.Unauthenticated, "获取token失败: %v", err)
+		}
+		if tokenString != "" {
+			return tokenString, nil
+		}
+	}
+	return "", nil
+}
+
+// PerRPCCredentials 按调用携带token的凭证，依次尝试各个token来源
+//
+//	grpc.Dial(addr, grpc.WithPerRPCCredentials(jwt.NewPerRPCCredentials(false, jwt.ForwardToken(), jwt.ServiceToken(nil, "order"))))
+type PerRPCCredentials struct {
+	sources []TokenSource
+	secure  bool
+}
+
+// NewPerRPCCredentials 创建按调用携带token的凭证，requireTLS 为 false 时可用于非TLS连接
+func NewPerRPCCredentials(requireTLS bool, sources ...TokenSource) *PerRPCCredentials {
+	return &PerRPCCredentials{sources: sources, secure: requireTLS}
+}
+
+// GetRequestMetadata 实现 credentials.PerRPCCredentials
+func (p *PerRPCCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	tokenString, err := firstToken(ctx, p.sources)
+	if err != nil || tokenString == "" {
+		return nil, err
+	}
+	return map[string]string{AuthorizationMetadataKey: "Bearer " + tokenString}, nil
+}
+
+// RequireTransportSecurity 实现 credentials.PerRPCCredentials
+func (p *PerRPCCredentials) RequireTransportSecurity() bool {
+	return p.secure
+}
+
+var _ credentials.PerRPCCredentials = (*PerRPCCredentials)(nil)
+
+// withOutgoingToken 出站元数据中没有token时追加
+func withOutgoingToken(ctx context.Context, sources []TokenSource) (context.Context, error) {
+	if md, ok := metadata.FromOutgoingContext(ctx); ok && len(md.Get(AuthorizationMetadataKey)) > 0 {
+		return ctx, nil
+	}
+	tokenString, err := firstToken(ctx, sources)
+	if err != nil || tokenString == "" {
+		return ctx, err
+	}
+	return metadata.AppendToOutgoingContext(ctx, AuthorizationMetadataKey, "Bearer "+tokenString), nil
+}
+
+// UnaryClientInterceptor gRPC客户端拦截器，为出站调用携带token，已显式设置的 authorization 不会被覆盖
+func UnaryClientInterceptor(sources ...TokenSource) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		ctx, err := withOutgoingToken(ctx, sources)
+		if err != nil {
+			return err
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+// StreamClientInterceptor gRPC流式调用客户端拦截器
+func StreamClientInterceptor(sources ...TokenSource) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		ctx, err := withOutgoingToken(ctx, sources)
+		if err != nil {
+			return nil, err
+		}
+		return streamer(ctx, desc, cc, method, opts...)
+	}
+}
+
+// IsServiceToken 判断声明是否为服务间调用token
+func IsServiceToken(claims *CustomClaims) bool {
+	if claims == nil || !strings.HasPrefix(claims.UserId, "service:") {
+		return false
+	}
+	for _, role := range claims.Roles {
+		if role == ServiceRole {
+			return true
+		}
+	}
+	return false
+}
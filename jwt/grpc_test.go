@@ -0,0 +1,76 @@
+package jwt
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Dev-Umb/go-pkg/errno"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestGRPCServerInterceptor(t *testing.T) {
+	m := NewManager(WithSecret("test-secret"))
+	interceptor := UnaryServerInterceptor(GRPCAuthOptions{Manager: m, SkipMethods: []string{"/health/Check"}})
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		user, ok := UserFromContext(ctx)
+		if !ok {
+			return "anonymous", nil
+		}
+		return user.UserId, nil
+	}
+	info := &grpc.UnaryServerInfo{FullMethod: "/order.Order/Get"}
+
+	token, _ := m.GenerateToken(UserInfo{UserId: "u1"})
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(AuthorizationMetadataKey, "Bearer "+token))
+	if resp, err := interceptor(ctx, nil, info, handler); err != nil || resp != "u1" {
+		t.Fatalf("expected authenticated call, got %v %v", resp, err)
+	}
+
+	_, err := interceptor(context.Background(), nil, info, handler)
+	if status.Code(err) != codes.Unauthenticated || errno.FromGRPCError(err) != errno.ErrTokenMissing {
+		t.Fatalf("expected missing token, got %v", err)
+	}
+
+	ctx = metadata.NewIncomingContext(context.Background(), metadata.Pairs(AuthorizationMetadataKey, "Bearer bad.token.value"))
+	if _, err := interceptor(ctx, nil, info, handler); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("expected unauthenticated, got %v", err)
+	}
+
+	if resp, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/health/Check"}, handler); err != nil || resp != "anonymous" {
+		t.Fatalf("skip method should pass, got %v %v", resp, err)
+	}
+}
+
+func TestGRPCClientInterceptor(t *testing.T) {
+	m := NewManager(WithSecret("test-secret"))
+	var sent string
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		md, _ := metadata.FromOutgoingContext(ctx)
+		if values := md.Get(AuthorizationMetadataKey); len(values) > 0 {
+			sent = ExtractBearerToken(values[0])
+		}
+		return nil
+	}
+	interceptor := UnaryClientInterceptor(ForwardToken(), ServiceToken(m, "order"))
+
+	// 转发调用方的token
+	if err := interceptor(WithToken(context.Background(), "caller-token"), "/m", nil, nil, nil, invoker); err != nil || sent != "caller-token" {
+		t.Fatalf("expected forwarded token, got %q %v", sent, err)
+	}
+
+	// 没有调用方token时签发服务间调用token
+	if err := interceptor(context.Background(), "/m", nil, nil, nil, invoker); err != nil {
+		t.Fatalf("invoke: %v", err)
+	}
+	claims, err := m.ParseToken(sent)
+	if err != nil || !IsServiceToken(claims) {
+		t.Fatalf("expected service token, got %+v %v", claims, err)
+	}
+	if ttl := claims.ExpiresAt.Sub(claims.IssuedAt.Time); ttl != DefaultServiceTokenTTL {
+		t.Fatalf("expected service token ttl %v, got %v", DefaultServiceTokenTTL, ttl)
+	}
+}
@@ -0,0 +1,303 @@
+package jwt
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Dev-Umb/go-pkg/logger"
+	"github.com/gin-gonic/gin"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// JWK 单个公钥（RFC 7517）
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid,omitempty"`
+	Use string `json:"use,omitempty"`
+	Alg string `json:"alg,omitempty"`
+	// RSA
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+	// EC / OKP
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+// JWKS 公钥集合
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKSProvider 可导出公钥集合的组件
+type JWKSProvider interface {
+	JWKS() (*JWKS, error)
+}
+
+// NewJWKS 将验证密钥转换为公钥集合，HMAC 密钥会被跳过
+func NewJWKS(keys ...VerifyKey) (*JWKS, error) {
+	set := &JWKS{Keys: make([]JWK, 0, len(keys))}
+	for _, k := range keys {
+		if _, ok := k.Key.([]byte); ok {
+			continue
+		}
+		jwk, err := NewJWK(k)
+		if err != nil {
+			return nil, err
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+	return set, nil
+}
+
+// NewJWK 将公钥转换为 JWK
+func NewJWK(k VerifyKey) (JWK, error) {
+	jwk := JWK{Kid: k.KeyID, Alg: k.Algorithm, Use: "sig"}
+	switch pub := publicKeyOf(k.Key).(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = encodeSegment(pub.N.Bytes())
+		jwk.E = encodeSegment(big.NewInt(int64(pub.E)).Bytes())
+	case *ecdsa.PublicKey:
+		size := (pub.Curve.Params().BitSize + 7) / 8
+		jwk.Kty = "EC"
+		jwk.Crv = pub.Curve.Params().Name
+		jwk.X = encodeSegment(pub.X.FillBytes(make([]byte, size)))
+		jwk.Y = encodeSegment(pub.Y.FillBytes(make([]byte, size)))
+	case ed25519.PublicKey:
+		jwk.Kty = "OKP"
+		jwk.Crv = "Ed25519"
+		jwk.X = encodeSegment(pub)
+	default:
+		return JWK{}, fmt.Errorf("不支持导出的公钥类型: %T", pub)
+	}
+	return jwk, nil
+}
+
+// VerifyKey 将 JWK 转换为验证密钥
+func (k JWK) VerifyKey() (VerifyKey, error) {
+	key := VerifyKey{KeyID: k.Kid, Algorithm: k.Alg}
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeSegment(k.N)
+		if err != nil {
+			return key, err
+		}
+		e, err := decodeSegment(k.E)
+		if err != nil {
+			return key, err
+		}
+		key.Key = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return key, fmt.Errorf("不支持的椭圆曲线: %s", k.Crv)
+		}
+		x, err := decodeSegment(k.X)
+		if err != nil {
+			return key, err
+		}
+		y, err := decodeSegment(k.Y)
+		if err != nil {
+			return key, err
+		}
+		key.Key = &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return key, fmt.Errorf("不支持的OKP曲线: %s", k.Crv)
+		}
+		x, err := decodeSegment(k.X)
+		if err != nil {
+			return key, err
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return key, fmt.Errorf("Ed25519公钥长度错误: %d", len(x))
+		}
+		key.Key = ed25519.PublicKey(x)
+	default:
+		return key, fmt.Errorf("不支持的密钥类型: %s", k.Kty)
+	}
+
+	if key.Algorithm == "" {
+		alg, err := AlgorithmForKey(key.Key)
+		if err != nil {
+			return key, err
+		}
+		key.Algorithm = alg
+	}
+	return key, nil
+}
+
+func encodeSegment(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeSegment(s string) ([]byte, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("JWK字段解码失败: %v", err)
+	}
+	return b, nil
+}
+
+// JWKSHandler 发布公钥集合的 gin 处理函数，通常挂载在 /.well-known/jwks.json
+// 按 JWKS 标准格式直接输出，不使用统一响应结构
+func JWKSHandler(provider JWKSProvider) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		set, err := provider.JWKS()
+		if err != nil {
+			logger.Errorf(c, "导出JWKS失败: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "jwks unavailable"})
+			return
+		}
+		c.Header("Cache-Control", "public, max-age=300")
+		c.JSON(http.StatusOK, set)
+	}
+}
+
+// RemoteJWKSOption 远程JWKS验证器选项
+type RemoteJWKSOption func(*RemoteJWKSVerifier)
+
+// WithJWKSHTTPClient 设置拉取JWKS使用的HTTP客户端
+func WithJWKSHTTPClient(client *http.Client) RemoteJWKSOption {
+	return func(v *RemoteJWKSVerifier) {
+		v.client = client
+	}
+}
+
+// WithJWKSCacheTTL 设置JWKS缓存时间
+func WithJWKSCacheTTL(ttl time.Duration) RemoteJWKSOption {
+	return func(v *RemoteJWKSVerifier) {
+		v.ttl = ttl
+	}
+}
+
+// WithJWKSMinRefreshInterval 设置遇到未知kid时强制刷新的最小间隔，防止恶意token导致频繁拉取
+func WithJWKSMinRefreshInterval(interval time.Duration) RemoteJWKSOption {
+	return func(v *RemoteJWKSVerifier) {
+		v.minRefreshInterval = interval
+	}
+}
+
+// RemoteJWKSVerifier 从远程地址拉取并缓存公钥集合的验证器
+type RemoteJWKSVerifier struct {
+	url                string
+	client             *http.Client
+	ttl                time.Duration
+	minRefreshInterval time.Duration
+
+	mu          sync.RWMutex
+	keys        map[string]VerifyKey
+	fetchedAt   time.Time
+	lastAttempt time.Time
+	refreshMu   sync.Mutex
+}
+
+// NewRemoteJWKSVerifier 创建远程JWKS验证器，首次验证时拉取公钥
+func NewRemoteJWKSVerifier(url string, opts ...RemoteJWKSOption) *RemoteJWKSVerifier {
+	v := &RemoteJWKSVerifier{
+		url:                url,
+		client:             &http.Client{Timeout: 5 * time.Second},
+		ttl:                10 * time.Minute,
+		minRefreshInterval: 30 * time.Second,
+		keys:               make(map[string]VerifyKey),
+	}
+	for _, opt := range opts {
+		opt(v)
+	}
+	return v
+}
+
+// Keyfunc 根据 kid 查找公钥，缓存过期或遇到未知 kid 时重新拉取
+func (v *RemoteJWKSVerifier) Keyfunc(token *jwt.Token) (interface{}, error) {
+	v.mu.RLock()
+	expired := time.Since(v.fetchedAt) > v.ttl
+	key, err := lookupKey(v.keys, token)
+	v.mu.RUnlock()
+
+	if err == nil && !expired {
+		return key, nil
+	}
+
+	v.mu.RLock()
+	canRefresh := time.Since(v.lastAttempt) >= v.minRefreshInterval
+	v.mu.RUnlock()
+	if !canRefresh {
+		return key, err
+	}
+
+	if refreshErr := v.Refresh(context.Background()); refreshErr != nil {
+		logger.Warnf(context.Background(), "刷新JWKS失败 %s: %v", v.url, refreshErr)
+		// 拉取失败时继续使用缓存中的公钥
+		return key, err
+	}
+
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	return lookupKey(v.keys, token)
+}
+
+// Refresh 立即拉取远程公钥集合
+func (v *RemoteJWKSVerifier) Refresh(ctx context.Context) error {
+	v.refreshMu.Lock()
+	defer v.refreshMu.Unlock()
+
+	v.mu.Lock()
+	v.lastAttempt = time.Now()
+	v.mu.Unlock()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, v.url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("请求JWKS失败: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("请求JWKS失败，状态码: %d", resp.StatusCode)
+	}
+
+	var set JWKS
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return fmt.Errorf("解析JWKS失败: %v", err)
+	}
+
+	keys := make(map[string]VerifyKey, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.VerifyKey()
+		if err != nil {
+			logger.Warnf(ctx, "跳过无法解析的JWK %s: %v", jwk.Kid, err)
+			continue
+		}
+		keys[key.KeyID] = key
+	}
+
+	v.mu.Lock()
+	v.keys = keys
+	v.fetchedAt = time.Now()
+	v.mu.Unlock()
+	return nil
+}
@@ -0,0 +1,105 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func generateTestKeys(t *testing.T) map[string]crypto.Signer {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate rsa key: %v", err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate ec key: %v", err)
+	}
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("generate ed25519 key: %v", err)
+	}
+	return map[string]crypto.Signer{"RS256": rsaKey, "ES256": ecKey, "EdDSA": edKey}
+}
+
+func TestAsymmetricSigners(t *testing.T) {
+	for alg, key := range generateTestKeys(t) {
+		der, err := x509.MarshalPKCS8PrivateKey(key)
+		if err != nil {
+			t.Fatalf("%s: marshal key: %v", alg, err)
+		}
+		parsed, err := ParsePrivateKeyPEM(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+		if err != nil {
+			t.Fatalf("%s: parse pem: %v", alg, err)
+		}
+
+		s, err := NewSigner(parsed, "kid-"+alg)
+		if err != nil {
+			t.Fatalf("%s: new signer: %v", alg, err)
+		}
+		if s.Algorithm() != alg {
+			t.Fatalf("expected %s, got %s", alg, s.Algorithm())
+		}
+
+		token, err := GenerateTokenWith(s, UserInfo{UserId: "u-" + alg})
+		if err != nil {
+			t.Fatalf("%s: sign: %v", alg, err)
+		}
+		claims, err := ParseTokenWith(s.Verifier(), token)
+		if err != nil || claims.UserId != "u-"+alg {
+			t.Fatalf("%s: verify: %v %#v", alg, err, claims)
+		}
+
+		// HMAC 验证器不能接受非对称签名的token
+		if _, err := ParseTokenWith(NewVerifier(VerifyKey{KeyID: "kid-" + alg, Algorithm: "HS256", Key: []byte("secret")}), token); err == nil {
+			t.Fatalf("%s: expected algorithm mismatch", alg)
+		}
+	}
+}
+
+func TestRemoteJWKSVerifier(t *testing.T) {
+	keys := generateTestKeys(t)
+	var signers []*KeySigner
+	var verifyKeys []VerifyKey
+	for alg, key := range keys {
+		s, err := NewSigner(key, "kid-"+alg)
+		if err != nil {
+			t.Fatalf("%s: new signer: %v", alg, err)
+		}
+		signers = append(signers, s)
+		verifyKeys = append(verifyKeys, s.VerifyKey())
+	}
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/.well-known/jwks.json", JWKSHandler(NewVerifier(verifyKeys...)))
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	remote := NewRemoteJWKSVerifier(server.URL + "/.well-known/jwks.json")
+	for _, s := range signers {
+		token, err := GenerateTokenWith(s, UserInfo{UserId: s.KeyID()})
+		if err != nil {
+			t.Fatalf("sign: %v", err)
+		}
+		claims, err := ParseTokenWith(remote, token)
+		if err != nil || claims.UserId != s.KeyID() {
+			t.Fatalf("%s: remote verify: %v", s.Algorithm(), err)
+		}
+	}
+
+	other, _ := NewSigner(keys["RS256"], "unknown-kid")
+	token, _ := GenerateTokenWith(other, UserInfo{})
+	if _, err := ParseTokenWith(remote, token); err == nil {
+		t.Fatalf("expected unknown kid to be rejected")
+	}
+}
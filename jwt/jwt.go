@@ -20,112 +20,106 @@ import (
 )
 
 type UserInfo struct {
-	UnionId   string `json:"union_id"`   // 用户在通用账户平台的唯一身份标识
-	OpenId    string `json:"open_id"`    // 用户在特定业务平台的身份标识
-	UserId    string `json:"user_id"`    // 用户ID
-	UserName  string `json:"user_name"`  // 用户名
-	AvatarURL string `json:"avatar_url"` //
+	UnionId   string   `json:"union_id"`         // 用户在通用账户平台的唯一身份标识
+	OpenId    string   `json:"open_id"`          // 用户在特定业务平台的身份标识
+	UserId    string   `json:"user_id"`          // 用户ID
+	UserName  string   `json:"user_name"`        // 用户名
+	AvatarURL string   `json:"avatar_url"`       //
+	Roles     []string `json:"roles,omitempty"`  // 角色，见 jwt/authz
+	Scopes    []string `json:"scopes,omitempty"` // 权限范围，如 order:write
 }
 
 type CustomClaims struct {
 	UserInfo
+	TokenType string `json:"token_type,omitempty"` // 令牌类型 access/refresh，旧版本签发的token为空
+	FamilyID  string `json:"fid,omitempty"`        // 令牌族ID，同一次登录轮换出的令牌共享，用于整体撤销
 	jwt.RegisteredClaims
 }
 
-var jwtSecret = "JWT_SECRET"
+// defaultTokenTTL GenerateToken 和 RefreshToken 签发token的默认有效期
+const defaultTokenTTL = time.Hour * 24 * 30
 
+// InitJwtSecret 设置默认管理器的 HS256 密钥
 func InitJwtSecret(targetJwtSecret string) {
-	jwtSecret = targetJwtSecret
+	defaultManager.Apply(WithSecret(targetJwtSecret))
 }
 
-// GenerateToken 生成token
+// UseSigner 设置默认管理器的签名器，例如使用 RS256/ES256/EdDSA 私钥签名
+func UseSigner(s Signer) {
+	defaultManager.Apply(WithSigner(s))
+}
+
+// UseVerifier 设置默认管理器的验证器，例如只持有公钥或使用远程JWKS的服务
+func UseVerifier(v Verifier) {
+	defaultManager.Apply(WithVerifier(v))
+}
+
+// UseRevocationStore 设置默认管理器的撤销存储
+func UseRevocationStore(store RevocationStore) {
+	defaultManager.Apply(WithRevocationStore(store))
+}
+
+// GenerateToken 使用默认管理器生成token
 func GenerateToken(user UserInfo) (string, error) {
+	return defaultManager.GenerateToken(user)
+}
+
+// GenerateTokenWith 使用指定签名器生成token，不写入签发者和受众
+func GenerateTokenWith(s Signer, user UserInfo) (string, error) {
 	// 创建声明
+	now := time.Now()
 	claims := CustomClaims{
 		UserInfo: user,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour * 24 * 30)), // 24小时过期
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			NotBefore: jwt.NewNumericDate(time.Now()),
+			Subject:   user.UserId,
+			ID:        newTokenID(),
+			ExpiresAt: jwt.NewNumericDate(now.Add(defaultTokenTTL)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
 		},
 	}
 
-	// 创建token
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-
-	// 获取密钥
-	if jwtSecret == "" {
-		logger.Errorf(context.Background(), "JWT密钥未配置")
-		return "", errors.New("JWT密钥未配置")
-	}
-
 	// 签名token
-	return token.SignedString([]byte(jwtSecret))
+	return s.Sign(claims)
 }
 
-// ParseToken 解析token
+// ParseToken 使用默认管理器解析token
+// 拒绝刷新令牌；设置了撤销存储时，已撤销的token返回 errno.ErrTokenRevoked
 func ParseToken(tokenString string) (*CustomClaims, error) {
-	// 获取密钥
-	if jwtSecret == "" {
-		logger.Errorf(context.Background(), "JWT密钥未配置")
-		return nil, errors.New("JWT密钥未配置")
-	}
+	return defaultManager.ParseToken(tokenString)
+}
 
+// ParseTokenWith 使用指定验证器解析token，只校验签名和有效期，拒绝刷新令牌
+func ParseTokenWith(v Verifier, tokenString string) (*CustomClaims, error) {
 	// 解析token
-	token, err := jwt.ParseWithClaims(tokenString, &CustomClaims{}, func(token *jwt.Token) (interface{}, error) {
-		return []byte(jwtSecret), nil
-	})
-
+	token, err := jwt.ParseWithClaims(tokenString, &CustomClaims{}, v.Keyfunc)
 	if err != nil {
 		return nil, err
 	}
 
 	// 验证token
 	if claims, ok := token.Claims.(*CustomClaims); ok && token.Valid {
+		if claims.TokenType == TokenTypeRefresh {
+			return nil, errno.ErrTokenInvalid
+		}
 		return claims, nil
 	}
 
 	return nil, errors.New("无效的token")
 }
 
-// RefreshToken 刷新token
+// RefreshToken 使用默认管理器刷新token
 func RefreshToken(tokenString string) (string, error) {
-	// 解析token
-	claims, err := ParseToken(tokenString)
-	if err != nil {
-		return "", err
-	}
-
-	// 更新过期时间
-	claims.ExpiresAt = jwt.NewNumericDate(time.Now().Add(time.Hour * 24))
-
-	// 创建新token
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-
-	// 签名token
-	return token.SignedString([]byte(jwtSecret))
+	return defaultManager.RefreshToken(tokenString)
 }
 
 // IsJwtTokenValid 判断jwt token是否有效
 func IsJwtTokenValid(tokenString string) (bool, error) {
-	// Parse the token
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, jwt.ErrSignatureInvalid
-		}
-		return []byte(jwtSecret), nil
-	})
+	valid, err := defaultManager.IsTokenValid(tokenString)
 	if err != nil {
 		logger.Errorf(context.Background(), "parse jwt token error: %v", err)
-		return false, err
-	}
-
-	// Check if the token is valid
-	if _, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-		return true, nil
-	} else {
-		return false, errno.InvalidTokenError
 	}
+	return valid, err
 }
 
 // ExtractBearerToken 从Authorization头中提取Bearer token
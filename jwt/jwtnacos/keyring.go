@@ -0,0 +1,40 @@
+package jwtnacos
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Dev-Umb/go-pkg/jwt"
+	"github.com/Dev-Umb/go-pkg/logger"
+	"github.com/Dev-Umb/go-pkg/nacos_sdk"
+)
+
+// NewKeyring 从Nacos配置加载密钥环，并监听配置变更热更新
+// 变更后的配置解析失败时保留原有密钥，不影响已签发token的验证
+// 监听失败时返回错误，避免密钥轮换后服务仍使用旧密钥
+func NewKeyring(dataId, group string) (*jwt.Keyring, error) {
+	ctx := context.Background()
+	data, err := nacos_sdk.GetConfigValue(dataId, group)
+	if err != nil {
+		return nil, fmt.Errorf("从Nacos获取密钥环配置失败: %v", err)
+	}
+
+	k, _ := jwt.NewKeyring()
+	if err := k.LoadConfig([]byte(data)); err != nil {
+		return nil, err
+	}
+
+	err = nacos_sdk.ListenConfigChange(dataId, group, func(data string) {
+		if err := k.LoadConfig([]byte(data)); err != nil {
+			logger.Errorf(ctx, "密钥环热更新失败，继续使用原有密钥 [%s:%s]: %v", group, dataId, err)
+			return
+		}
+		logger.Infof(ctx, "密钥环已更新 [%s:%s]，当前签名密钥: %s", group, dataId, k.SigningKeyID())
+	})
+	if err != nil {
+		return nil, fmt.Errorf("监听密钥环配置失败 [%s:%s]: %v", group, dataId, err)
+	}
+
+	logger.Infof(ctx, "密钥环加载成功 [%s:%s]，当前签名密钥: %s", group, dataId, k.SigningKeyID())
+	return k, nil
+}
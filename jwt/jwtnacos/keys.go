@@ -0,0 +1,50 @@
+// Package jwtnacos 从Nacos配置加载JWT密钥，jwt 包本身不依赖Nacos
+package jwtnacos
+
+import (
+	"crypto"
+	"fmt"
+
+	"github.com/Dev-Umb/go-pkg/jwt"
+	"github.com/Dev-Umb/go-pkg/nacos_sdk"
+)
+
+// LoadPrivateKey 从Nacos配置加载PEM格式私钥
+func LoadPrivateKey(dataId, group string) (crypto.Signer, error) {
+	data, err := nacos_sdk.GetConfigValue(dataId, group)
+	if err != nil {
+		return nil, fmt.Errorf("从Nacos获取私钥失败: %v", err)
+	}
+	return jwt.ParsePrivateKeyPEM([]byte(data))
+}
+
+// LoadPublicKey 从Nacos配置加载PEM格式公钥
+func LoadPublicKey(dataId, group string) (crypto.PublicKey, error) {
+	data, err := nacos_sdk.GetConfigValue(dataId, group)
+	if err != nil {
+		return nil, fmt.Errorf("从Nacos获取公钥失败: %v", err)
+	}
+	return jwt.ParsePublicKeyPEM([]byte(data))
+}
+
+// NewSigner 从Nacos配置中的PEM私钥创建签名器
+func NewSigner(dataId, group, kid string) (*jwt.KeySigner, error) {
+	key, err := LoadPrivateKey(dataId, group)
+	if err != nil {
+		return nil, err
+	}
+	return jwt.NewSigner(key, kid)
+}
+
+// NewVerifier 从Nacos配置中的PEM公钥创建验证器
+func NewVerifier(dataId, group, kid string) (*jwt.KeyVerifier, error) {
+	key, err := LoadPublicKey(dataId, group)
+	if err != nil {
+		return nil, err
+	}
+	alg, err := jwt.AlgorithmForKey(key)
+	if err != nil {
+		return nil, err
+	}
+	return jwt.NewVerifier(jwt.VerifyKey{KeyID: kid, Algorithm: alg, Key: key}), nil
+}
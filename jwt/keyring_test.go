@@ -0,0 +1,68 @@
+package jwt
+
+import (
+	"testing"
+	"time"
+)
+
+func TestKeyringRotation(t *testing.T) {
+	ring, err := NewKeyring(RingKey{KeyID: "k1", Key: []byte("secret-1")})
+	if err != nil {
+		t.Fatalf("new keyring: %v", err)
+	}
+	if err := ring.SetSigningKey("k1"); err != nil {
+		t.Fatalf("set signing key: %v", err)
+	}
+	now := time.Now()
+	ring.now = func() time.Time { return now }
+
+	oldToken, err := GenerateTokenWith(ring, UserInfo{UserId: "u1"})
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+
+	if err := ring.Rotate(RingKey{KeyID: "k2", Key: []byte("secret-2")}, time.Hour); err != nil {
+		t.Fatalf("rotate: %v", err)
+	}
+	newToken, _ := GenerateTokenWith(ring, UserInfo{UserId: "u1"})
+
+	for _, token := range []string{oldToken, newToken} {
+		if _, err := ParseTokenWith(ring, token); err != nil {
+			t.Fatalf("token should be valid during grace period: %v", err)
+		}
+	}
+
+	now = now.Add(2 * time.Hour)
+	if _, err := ParseTokenWith(ring, oldToken); err == nil {
+		t.Fatalf("token signed by retired key should be rejected")
+	}
+	if _, err := ParseTokenWith(ring, newToken); err != nil {
+		t.Fatalf("token signed by current key should be valid: %v", err)
+	}
+}
+
+func TestKeyringLoadConfig(t *testing.T) {
+	ring, _ := NewKeyring()
+	err := ring.LoadConfig([]byte(`{
+		"signing_kid": "b",
+		"keys": [
+			{"kid": "a", "alg": "HS256", "secret": "secret-a"},
+			{"kid": "b", "alg": "HS256", "secret": "secret-b"}
+		]
+	}`))
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	token, err := GenerateTokenWith(ring, UserInfo{UserId: "u1"})
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+
+	// 配置错误时保留原有密钥
+	if err := ring.LoadConfig([]byte(`{"signing_kid": "c", "keys": []}`)); err == nil {
+		t.Fatalf("expected invalid config error")
+	}
+	if _, err := ParseTokenWith(ring, token); err != nil || ring.SigningKeyID() != "b" {
+		t.Fatalf("keyring should be unchanged: %v", err)
+	}
+}
@@ -0,0 +1,123 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// ParsePrivateKeyPEM 解析PEM格式私钥
+// 支持 PKCS#1(RSA)、SEC1(EC) 和 PKCS#8，返回 *rsa.PrivateKey、*ecdsa.PrivateKey 或 ed25519.PrivateKey
+func ParsePrivateKeyPEM(data []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("私钥不是有效的PEM格式")
+	}
+
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		return x509.ParseECPrivateKey(block.Bytes)
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("解析私钥失败: %v", err)
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("不支持的私钥类型: %T", key)
+	}
+	return signer, nil
+}
+
+// ParsePublicKeyPEM 解析PEM格式公钥，支持 PKIX、PKCS#1(RSA) 和 X.509 证书
+func ParsePublicKeyPEM(data []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("公钥不是有效的PEM格式")
+	}
+
+	switch block.Type {
+	case "RSA PUBLIC KEY":
+		return x509.ParsePKCS1PublicKey(block.Bytes)
+	case "CERTIFICATE":
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("解析证书失败: %v", err)
+		}
+		return cert.PublicKey, nil
+	}
+
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("解析公钥失败: %v", err)
+	}
+	return key, nil
+}
+
+// LoadPrivateKeyFile 从文件加载PEM格式私钥
+func LoadPrivateKeyFile(path string) (crypto.Signer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取私钥文件失败: %v", err)
+	}
+	return ParsePrivateKeyPEM(data)
+}
+
+// LoadPublicKeyFile 从文件加载PEM格式公钥
+func LoadPublicKeyFile(path string) (crypto.PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取公钥文件失败: %v", err)
+	}
+	return ParsePublicKeyPEM(data)
+}
+
+// AlgorithmForKey 根据密钥类型推断签名算法
+// RSA 使用 RS256，P-256 使用 ES256，P-384 使用 ES384，P-521 使用 ES512，Ed25519 使用 EdDSA，[]byte 使用 HS256
+func AlgorithmForKey(key interface{}) (string, error) {
+	switch k := key.(type) {
+	case []byte:
+		return "HS256", nil
+	case *rsa.PrivateKey, *rsa.PublicKey:
+		return "RS256", nil
+	case *ecdsa.PrivateKey:
+		return ecAlgorithm(k.Curve)
+	case *ecdsa.PublicKey:
+		return ecAlgorithm(k.Curve)
+	case ed25519.PrivateKey, ed25519.PublicKey:
+		return "EdDSA", nil
+	default:
+		return "", fmt.Errorf("不支持的密钥类型: %T", key)
+	}
+}
+
+func ecAlgorithm(curve elliptic.Curve) (string, error) {
+	switch curve {
+	case elliptic.P256():
+		return "ES256", nil
+	case elliptic.P384():
+		return "ES384", nil
+	case elliptic.P521():
+		return "ES512", nil
+	default:
+		return "", fmt.Errorf("不支持的椭圆曲线: %s", curve.Params().Name)
+	}
+}
+
+// publicKeyOf 获取私钥对应的公钥，HMAC 密钥原样返回
+func publicKeyOf(key interface{}) interface{} {
+	if signer, ok := key.(crypto.Signer); ok {
+		return signer.Public()
+	}
+	return key
+}
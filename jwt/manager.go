@@ -0,0 +1,417 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Dev-Umb/go-pkg/errno"
+	"github.com/Dev-Umb/go-pkg/logger"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// 可在 WithRequiredClaims 中要求必须存在的注册声明
+const (
+	ClaimIssuer    = "iss"
+	ClaimSubject   = "sub"
+	ClaimAudience  = "aud"
+	ClaimExpiresAt = "exp"
+	ClaimNotBefore = "nbf"
+	ClaimIssuedAt  = "iat"
+	ClaimID        = "jti"
+)
+
+// Manager JWT管理器，持有签发和校验token所需的全部配置
+// 包级别函数（GenerateToken、ParseToken 等）委托给默认管理器，见 DefaultManager
+type Manager struct {
+	mu sync.RWMutex
+
+	issuer         string
+	audiences      []string
+	ttl            time.Duration
+	accessTTL      time.Duration
+	refreshTTL     time.Duration
+	serviceTTL     time.Duration
+	leeway         time.Duration
+	algorithms     []string
+	requiredClaims []string
+
+	secret     []byte
+	signer     Signer
+	verifier   Verifier
+	revocation RevocationStore
+	now        func() time.Time
+}
+
+// ManagerOption 管理器配置选项
+type ManagerOption func(*Manager)
+
+// WithIssuer 设置签发者，签发时写入 iss，校验时要求 iss 一致
+func WithIssuer(issuer string) ManagerOption {
+	return func(m *Manager) {
+		m.issuer = issuer
+	}
+}
+
+// WithAudiences 设置受众，签发时写入 aud，校验时要求 aud 至少包含其中一个
+func WithAudiences(audiences ...string) ManagerOption {
+	return func(m *Manager) {
+		m.audiences = audiences
+	}
+}
+
+// WithTTL 设置 GenerateToken 签发token的有效期，小于等于0时保持不变
+func WithTTL(ttl time.Duration) ManagerOption {
+	return func(m *Manager) {
+		if ttl > 0 {
+			m.ttl = ttl
+		}
+	}
+}
+
+// WithTokenPairTTL 设置访问令牌和刷新令牌的有效期，小于等于0的值保持不变
+func WithTokenPairTTL(access, refresh time.Duration) ManagerOption {
+	return func(m *Manager) {
+		if access > 0 {
+			m.accessTTL = access
+		}
+		if refresh > 0 {
+			m.refreshTTL = refresh
+		}
+	}
+}
+
+// WithServiceTokenTTL 设置 ServiceToken 签发的服务间调用token的有效期，小于等于0时保持不变
+func WithServiceTokenTTL(ttl time.Duration) ManagerOption {
+	return func(m *Manager) {
+		if ttl > 0 {
+			m.serviceTTL = ttl
+		}
+	}
+}
+
+// WithLeeway 设置校验 exp/nbf/iat 时允许的时钟偏差
+func WithLeeway(leeway time.Duration) ManagerOption {
+	return func(m *Manager) {
+		m.leeway = leeway
+	}
+}
+
+// WithAllowedAlgorithms 设置允许的签名算法，为空时允许验证器支持的所有算法
+func WithAllowedAlgorithms(algorithms ...string) ManagerOption {
+	return func(m *Manager) {
+		m.algorithms = algorithms
+	}
+}
+
+// WithRequiredClaims 设置必须存在的注册声明，如 ClaimSubject、ClaimID
+func WithRequiredClaims(claims ...string) ManagerOption {
+	return func(m *Manager) {
+		m.requiredClaims = claims
+	}
+}
+
+// WithSecret 使用 HMAC 密钥进行 HS256 签名和验证
+func WithSecret(secret string) ManagerOption {
+	return func(m *Manager) {
+		m.secret = []byte(secret)
+	}
+}
+
+// WithSigner 设置签名器
+func WithSigner(s Signer) ManagerOption {
+	return func(m *Manager) {
+		m.signer = s
+	}
+}
+
+// WithVerifier 设置验证器
+func WithVerifier(v Verifier) ManagerOption {
+	return func(m *Manager) {
+		m.verifier = v
+	}
+}
+
+// WithKeyring 使用密钥环同时作为签名器和验证器
+func WithKeyring(k *Keyring) ManagerOption {
+	return func(m *Manager) {
+		m.signer = k
+		m.verifier = k
+	}
+}
+
+// WithRevocationStore 设置撤销存储
+func WithRevocationStore(store RevocationStore) ManagerOption {
+	return func(m *Manager) {
+		m.revocation = store
+	}
+}
+
+// NewManager 创建JWT管理器
+func NewManager(opts ...ManagerOption) *Manager {
+	m := &Manager{
+		ttl:        defaultTokenTTL,
+		accessTTL:  DefaultAccessTTL,
+		refreshTTL: DefaultRefreshTTL,
+		serviceTTL: DefaultServiceTokenTTL,
+		now:        time.Now,
+	}
+	m.Apply(opts...)
+	return m
+}
+
+// Apply 修改管理器配置
+func (m *Manager) Apply(opts ...ManagerOption) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, opt := range opts {
+		opt(m)
+	}
+}
+
+var defaultManager = NewManager(WithSecret("JWT_SECRET"))
+
+// DefaultManager 获取包级别函数使用的默认管理器
+func DefaultManager() *Manager {
+	return defaultManager
+}
+
+// SetDefaultManager 替换默认管理器，应在服务启动时调用
+func SetDefaultManager(m *Manager) {
+	if m != nil {
+		defaultManager = m
+	}
+}
+
+// currentSigner 获取签名器，未设置时使用 HMAC 密钥
+func (m *Manager) currentSigner() (Signer, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.signer != nil {
+		return m.signer, nil
+	}
+	if len(m.secret) == 0 {
+		logger.Errorf(context.Background(), "JWT密钥未配置")
+		return nil, errors.New("JWT密钥未配置")
+	}
+	return &KeySigner{method: jwt.SigningMethodHS256, key: m.secret}, nil
+}
+
+// currentVerifier 获取验证器，未设置时使用 HMAC 密钥
+func (m *Manager) currentVerifier() (Verifier, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.verifier != nil {
+		return m.verifier, nil
+	}
+	if len(m.secret) == 0 {
+		logger.Errorf(context.Background(), "JWT密钥未配置")
+		return nil, errors.New("JWT密钥未配置")
+	}
+	return NewVerifier(VerifyKey{Algorithm: jwt.SigningMethodHS256.Alg(), Key: m.secret}), nil
+}
+
+// HasRevocationStore 是否设置了撤销存储
+func (m *Manager) HasRevocationStore() bool {
+	return m.revocationStore() != nil
+}
+
+// revocationStore 获取撤销存储
+func (m *Manager) revocationStore() RevocationStore {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.revocation
+}
+
+// ttls 获取普通token、访问令牌和刷新令牌的有效期
+func (m *Manager) ttls() (ttl, access, refresh time.Duration) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.ttl, m.accessTTL, m.refreshTTL
+}
+
+func (m *Manager) serviceTokenTTL() time.Duration {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.serviceTTL
+}
+
+// fillRegistered 填充注册声明：iss、aud、sub、jti、iat、nbf、exp
+// 调用方已设置的字段不会被覆盖
+func (m *Manager) fillRegistered(rc *jwt.RegisteredClaims, subject string, ttl time.Duration) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	now := m.now()
+	if rc.Issuer == "" {
+		rc.Issuer = m.issuer
+	}
+	if len(rc.Audience) == 0 && len(m.audiences) > 0 {
+		rc.Audience = append(jwt.ClaimStrings(nil), m.audiences...)
+	}
+	if rc.Subject == "" {
+		rc.Subject = subject
+	}
+	if rc.ID == "" {
+		rc.ID = newTokenID()
+	}
+	if rc.IssuedAt == nil {
+		rc.IssuedAt = jwt.NewNumericDate(now)
+	}
+	if rc.NotBefore == nil {
+		rc.NotBefore = jwt.NewNumericDate(now)
+	}
+	if rc.ExpiresAt == nil {
+		rc.ExpiresAt = jwt.NewNumericDate(now.Add(ttl))
+	}
+}
+
+// parse 校验签名并解析到 claims，时间、签发者、受众和必需声明由 validateRegistered 校验
+func (m *Manager) parse(tokenString string, claims jwt.Claims) error {
+	rc, err := registeredClaimsOf(claims)
+	if err != nil {
+		return err
+	}
+	v, err := m.currentVerifier()
+	if err != nil {
+		return err
+	}
+
+	m.mu.RLock()
+	opts := []jwt.ParserOption{jwt.WithoutClaimsValidation()}
+	if len(m.algorithms) > 0 {
+		opts = append(opts, jwt.WithValidMethods(m.algorithms))
+	}
+	m.mu.RUnlock()
+
+	token, err := jwt.NewParser(opts...).ParseWithClaims(tokenString, claims, v.Keyfunc)
+	if err != nil {
+		return err
+	}
+	if !token.Valid {
+		return errors.New("无效的token")
+	}
+	return m.validateRegistered(rc)
+}
+
+// validateRegistered 校验注册声明
+func (m *Manager) validateRegistered(rc *jwt.RegisteredClaims) error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	present := map[string]bool{
+		ClaimIssuer:    rc.Issuer != "",
+		ClaimSubject:   rc.Subject != "",
+		ClaimAudience:  len(rc.Audience) > 0,
+		ClaimExpiresAt: rc.ExpiresAt != nil,
+		ClaimNotBefore: rc.NotBefore != nil,
+		ClaimIssuedAt:  rc.IssuedAt != nil,
+		ClaimID:        rc.ID != "",
+	}
+	for _, name := range m.requiredClaims {
+		if !present[name] {
+			return jwt.NewValidationError(fmt.Sprintf("缺少必需的声明: %s", name), jwt.ValidationErrorClaimsInvalid)
+		}
+	}
+
+	now := m.now()
+	if rc.ExpiresAt != nil && !now.Before(rc.ExpiresAt.Add(m.leeway)) {
+		return jwt.NewValidationError("token is expired", jwt.ValidationErrorExpired)
+	}
+	if rc.NotBefore != nil && now.Add(m.leeway).Before(rc.NotBefore.Time) {
+		return jwt.NewValidationError("token is not valid yet", jwt.ValidationErrorNotValidYet)
+	}
+	if rc.IssuedAt != nil && now.Add(m.leeway).Before(rc.IssuedAt.Time) {
+		return jwt.NewValidationError("token used before issued", jwt.ValidationErrorIssuedAt)
+	}
+	if m.issuer != "" && rc.Issuer != m.issuer {
+		return jwt.NewValidationError("token has invalid issuer", jwt.ValidationErrorIssuer)
+	}
+	if len(m.audiences) > 0 {
+		matched := false
+		for _, aud := range m.audiences {
+			if rc.VerifyAudience(aud, true) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return jwt.NewValidationError("token has invalid audience", jwt.ValidationErrorAudience)
+		}
+	}
+	return nil
+}
+
+// checkRevoked 检查token或其所属令牌族是否已被撤销
+func (m *Manager) checkRevoked(ctx context.Context, jti, familyID string) error {
+	store := m.revocationStore()
+	if store == nil {
+		return nil
+	}
+
+	ids := make([]string, 0, 2)
+	if jti != "" {
+		ids = append(ids, jti)
+	}
+	if familyID != "" {
+		ids = append(ids, familyRevocationID(familyID))
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	revoked, err := store.IsRevoked(ctx, ids...)
+	if err != nil {
+		logger.Errorf(ctx, "查询token撤销状态失败: %v", err)
+		return errno.New(errno.ErrTokenValidate, err)
+	}
+	if revoked {
+		return errno.ErrTokenRevoked
+	}
+	return nil
+}
+
+// GenerateToken 签发token，有效期为 WithTTL 配置的值
+func (m *Manager) GenerateToken(user UserInfo) (string, error) {
+	return GenerateWith(m, &CustomClaims{UserInfo: user})
+}
+
+// ParseToken 解析并校验token
+// 拒绝刷新令牌；设置了撤销存储时，已撤销的token返回 errno.ErrTokenRevoked
+func (m *Manager) ParseToken(tokenString string) (*CustomClaims, error) {
+	return ParseWith[CustomClaims](m, tokenString)
+}
+
+// parseClaims 只校验签名和注册声明，不检查令牌类型和撤销状态
+func (m *Manager) parseClaims(tokenString string) (*CustomClaims, error) {
+	claims := &CustomClaims{}
+	if err := m.parse(tokenString, claims); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+// RefreshToken 使用未过期的token重新签发一个新token，有效期与 GenerateToken 一致
+func (m *Manager) RefreshToken(tokenString string) (string, error) {
+	claims, err := m.ParseToken(tokenString)
+	if err != nil {
+		return "", err
+	}
+
+	claims.ID = ""
+	claims.IssuedAt = nil
+	claims.NotBefore = nil
+	claims.ExpiresAt = nil
+	return GenerateWith(m, claims)
+}
+
+// IsTokenValid 判断token是否有效
+func (m *Manager) IsTokenValid(tokenString string) (bool, error) {
+	if _, err := m.ParseToken(tokenString); err != nil {
+		return false, err
+	}
+	return true, nil
+}
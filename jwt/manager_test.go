@@ -0,0 +1,75 @@
+package jwt
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Dev-Umb/go-pkg/errno"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+func TestManagerIssuerAudience(t *testing.T) {
+	m := NewManager(WithSecret("test-secret"), WithIssuer("auth"), WithAudiences("api", "admin"))
+	token, err := m.GenerateToken(UserInfo{UserId: "u1"})
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	claims, err := m.ParseToken(token)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if claims.Issuer != "auth" || claims.Subject != "u1" || claims.ID == "" || len(claims.Audience) != 2 {
+		t.Fatalf("unexpected registered claims: %+v", claims.RegisteredClaims)
+	}
+
+	other := NewManager(WithSecret("test-secret"), WithIssuer("other"))
+	if _, err := other.ParseToken(token); !errors.Is(err, jwt.ErrTokenInvalidIssuer) {
+		t.Fatalf("expected invalid issuer, got %v", err)
+	}
+	other = NewManager(WithSecret("test-secret"), WithAudiences("billing"))
+	if _, err := other.ParseToken(token); !errors.Is(err, jwt.ErrTokenInvalidAudience) {
+		t.Fatalf("expected invalid audience, got %v", err)
+	}
+}
+
+func TestManagerLeeway(t *testing.T) {
+	now := time.Now()
+	m := NewManager(WithSecret("test-secret"), WithTTL(time.Minute))
+	m.now = func() time.Time { return now }
+	token, err := m.GenerateToken(UserInfo{UserId: "u1"})
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+
+	m.now = func() time.Time { return now.Add(90 * time.Second) }
+	if _, err := m.ParseToken(token); TokenErrno(err) != errno.ErrTokenExpired {
+		t.Fatalf("expected expired, got %v", err)
+	}
+
+	m.Apply(WithLeeway(time.Minute))
+	if _, err := m.ParseToken(token); err != nil {
+		t.Fatalf("expected token within leeway to be valid: %v", err)
+	}
+}
+
+func TestManagerAllowedAlgorithmsAndRequiredClaims(t *testing.T) {
+	m := NewManager(WithSecret("test-secret"), WithAllowedAlgorithms("RS256"))
+	token, err := m.GenerateToken(UserInfo{UserId: "u1"})
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	if _, err := m.ParseToken(token); err == nil {
+		t.Fatalf("HS256 token must be rejected")
+	}
+
+	legacy, err := GenerateTokenWith(&KeySigner{method: jwt.SigningMethodHS256, key: []byte("test-secret")}, UserInfo{})
+	if err != nil {
+		t.Fatalf("generate legacy: %v", err)
+	}
+	m = NewManager(WithSecret("test-secret"), WithRequiredClaims(ClaimSubject))
+	if _, err := m.ParseToken(legacy); TokenErrno(err) != errno.ErrTokenInvalid {
+		t.Fatalf("expected missing sub to be rejected, got %v", err)
+	}
+}
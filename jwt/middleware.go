@@ -0,0 +1,174 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Dev-Umb/go-pkg/core"
+	"github.com/Dev-Umb/go-pkg/errno"
+	"github.com/Dev-Umb/go-pkg/logger"
+	"github.com/gin-gonic/gin"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// gin上下文中存储认证信息的键
+const (
+	TokenKey  = "token"     // 原始token，GetJwtToken 优先读取
+	ClaimsKey = "jwtClaims" // *CustomClaims
+)
+
+// claimsCtxKey 请求上下文中存储 *CustomClaims 的键
+type claimsCtxKey struct{}
+
+// tokenCtxKey 请求上下文中存储原始token的键，供下游gRPC调用转发
+type tokenCtxKey struct{}
+
+// AuthOptions 认证中间件配置
+type AuthOptions struct {
+	// Header 读取token的请求头，默认 Authorization，支持 Bearer 前缀
+	Header string
+	// Cookie 读取token的cookie名，为空表示不从cookie读取
+	Cookie string
+	// Query 读取token的查询参数名，为空表示不从查询参数读取（适用于 WebSocket、SSE 等无法设置请求头的场景）
+	Query string
+	// Optional 可选认证，未携带token时匿名放行；携带了无效token仍然拒绝
+	Optional bool
+	// Manager 校验token使用的管理器，为空时使用默认管理器
+	Manager *Manager
+}
+
+// AuthMiddleware gin认证中间件
+// 依次从请求头、cookie、查询参数中提取token，校验通过后将声明写入gin上下文和请求上下文
+// 失败时通过 core.SendResponse 返回 ErrTokenMissing/ErrTokenExpired/ErrTokenInvalid 并中止请求
+func AuthMiddleware(opts AuthOptions) gin.HandlerFunc {
+	if opts.Header == "" {
+		opts.Header = "Authorization"
+	}
+
+	return func(c *gin.Context) {
+		tokenString := extractToken(c, opts)
+		if tokenString == "" {
+			if opts.Optional {
+				c.Next()
+				return
+			}
+			core.SendResponse(c, errno.ErrTokenMissing, nil)
+			c.Abort()
+			return
+		}
+
+		m := opts.Manager
+		if m == nil {
+			m = defaultManager
+		}
+		claims, err := m.ParseToken(tokenString)
+		if err != nil {
+			logger.Warnf(c, "jwt auth failed: %v", err)
+			core.SendResponse(c, TokenErrno(err), nil)
+			c.Abort()
+			return
+		}
+
+		SetClaims(c, tokenString, claims)
+		c.Next()
+	}
+}
+
+// extractToken 按配置顺序提取token
+func extractToken(c *gin.Context, opts AuthOptions) string {
+	if header := c.GetHeader(opts.Header); header != "" {
+		return ExtractBearerToken(header)
+	}
+	if opts.Cookie != "" {
+		if cookie, err := c.Cookie(opts.Cookie); err == nil && cookie != "" {
+			return cookie
+		}
+	}
+	if opts.Query != "" {
+		return c.Query(opts.Query)
+	}
+	return ""
+}
+
+// TokenErrno 将token解析错误映射为错误码
+func TokenErrno(err error) *errno.Errno {
+	var e *errno.Errno
+	var wrapped *errno.Err
+	switch {
+	case errors.As(err, &e):
+		return e
+	case errors.As(err, &wrapped):
+		if registered, ok := errno.Lookup(wrapped.Code); ok {
+			return registered
+		}
+		return errno.ErrTokenValidate
+	case errors.Is(err, jwt.ErrTokenExpired):
+		return errno.ErrTokenExpired
+	default:
+		return errno.ErrTokenInvalid
+	}
+}
+
+// SetClaims 将认证信息写入gin上下文和请求上下文，供自定义认证流程使用
+func SetClaims(c *gin.Context, tokenString string, claims *CustomClaims) {
+	c.Set(TokenKey, tokenString)
+	c.Set(ClaimsKey, claims)
+	c.Request = c.Request.WithContext(WithToken(WithClaims(c.Request.Context(), claims), tokenString))
+}
+
+// WithClaims 将声明写入context
+func WithClaims(ctx context.Context, claims *CustomClaims) context.Context {
+	return context.WithValue(ctx, claimsCtxKey{}, claims)
+}
+
+// WithToken 将原始token写入context
+func WithToken(ctx context.Context, tokenString string) context.Context {
+	return context.WithValue(ctx, tokenCtxKey{}, tokenString)
+}
+
+// TokenFromContext 从context中获取原始token
+func TokenFromContext(ctx context.Context) (string, bool) {
+	if ctx == nil {
+		return "", false
+	}
+	tokenString, ok := ctx.Value(tokenCtxKey{}).(string)
+	return tokenString, ok && tokenString != ""
+}
+
+// ClaimsFromContext 从context中获取声明
+func ClaimsFromContext(ctx context.Context) (*CustomClaims, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+	claims, ok := ctx.Value(claimsCtxKey{}).(*CustomClaims)
+	return claims, ok
+}
+
+// UserFromContext 从context中获取当前用户
+func UserFromContext(ctx context.Context) (*UserInfo, bool) {
+	claims, ok := ClaimsFromContext(ctx)
+	if !ok {
+		return nil, false
+	}
+	return &claims.UserInfo, true
+}
+
+// CurrentClaims 获取当前请求的声明，未认证时返回 false
+func CurrentClaims(c *gin.Context) (*CustomClaims, bool) {
+	if value, exists := c.Get(ClaimsKey); exists {
+		if claims, ok := value.(*CustomClaims); ok {
+			return claims, true
+		}
+	}
+	return ClaimsFromContext(c.Request.Context())
+}
+
+// CurrentUser 获取当前请求的用户，未认证时返回 false
+func CurrentUser(c *gin.Context) (*UserInfo, bool) {
+	claims, ok := CurrentClaims(c)
+	if !ok {
+		return nil, false
+	}
+	return &claims.UserInfo, true
+}
@@ -0,0 +1,93 @@
+package jwt
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Dev-Umb/go-pkg/errno"
+	"github.com/gin-gonic/gin"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+func newAuthRouter(opts AuthOptions) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/me", AuthMiddleware(opts), func(c *gin.Context) {
+		user, ok := CurrentUser(c)
+		if !ok {
+			c.JSON(http.StatusOK, gin.H{"code": 0, "anonymous": true})
+			return
+		}
+		ctxUser, _ := UserFromContext(c.Request.Context())
+		c.JSON(http.StatusOK, gin.H{"code": 0, "user_id": user.UserId, "ctx_user_id": ctxUser.UserId})
+	})
+	return r
+}
+
+func doAuthRequest(r *gin.Engine, req *http.Request) map[string]interface{} {
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	var body map[string]interface{}
+	_ = json.Unmarshal(w.Body.Bytes(), &body)
+	return body
+}
+
+func TestAuthMiddleware(t *testing.T) {
+	InitJwtSecret("test-secret")
+	token, err := GenerateToken(UserInfo{UserId: "u1"})
+	if err != nil {
+		t.Fatalf("generate token: %v", err)
+	}
+	r := newAuthRouter(AuthOptions{Cookie: "access_token", Query: "token"})
+
+	req := httptest.NewRequest(http.MethodGet, "/me", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	if body := doAuthRequest(r, req); body["user_id"] != "u1" || body["ctx_user_id"] != "u1" {
+		t.Fatalf("header auth failed: %v", body)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/me?token="+token, nil)
+	if body := doAuthRequest(r, req); body["user_id"] != "u1" {
+		t.Fatalf("query auth failed: %v", body)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/me", nil)
+	req.AddCookie(&http.Cookie{Name: "access_token", Value: token})
+	if body := doAuthRequest(r, req); body["user_id"] != "u1" {
+		t.Fatalf("cookie auth failed: %v", body)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/me", nil)
+	if body := doAuthRequest(r, req); body["code"] != float64(errno.ErrTokenMissing.Code) {
+		t.Fatalf("expected missing token, got %v", body)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/me", nil)
+	req.Header.Set("Authorization", "Bearer invalid.token.value")
+	if body := doAuthRequest(r, req); body["code"] != float64(errno.ErrTokenInvalid.Code) {
+		t.Fatalf("expected invalid token, got %v", body)
+	}
+}
+
+func TestAuthMiddlewareExpiredAndOptional(t *testing.T) {
+	InitJwtSecret("test-secret")
+	expired, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, CustomClaims{
+		RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(time.Now().Add(-time.Minute))},
+	}).SignedString([]byte("test-secret"))
+
+	r := newAuthRouter(AuthOptions{Optional: true})
+	req := httptest.NewRequest(http.MethodGet, "/me", nil)
+	if body := doAuthRequest(r, req); body["anonymous"] != true {
+		t.Fatalf("expected anonymous access, got %v", body)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/me", nil)
+	req.Header.Set("Authorization", "Bearer "+expired)
+	if body := doAuthRequest(r, req); body["code"] != float64(errno.ErrTokenExpired.Code) {
+		t.Fatalf("expected expired token, got %v", body)
+	}
+}
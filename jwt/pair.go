@@ -0,0 +1,209 @@
+package jwt
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/Dev-Umb/go-pkg/errno"
+	"github.com/Dev-Umb/go-pkg/logger"
+)
+
+// 令牌类型
+const (
+	TokenTypeAccess  = "access"
+	TokenTypeRefresh = "refresh"
+)
+
+// 默认令牌有效期
+const (
+	DefaultAccessTTL  = 2 * time.Hour
+	DefaultRefreshTTL = 30 * 24 * time.Hour
+)
+
+// TokenPair 访问令牌和刷新令牌
+type TokenPair struct {
+	AccessToken      string    `json:"access_token"`
+	RefreshToken     string    `json:"refresh_token"`
+	TokenType        string    `json:"token_type"`
+	AccessExpiresAt  time.Time `json:"access_expires_at"`
+	RefreshExpiresAt time.Time `json:"refresh_expires_at"`
+}
+
+// SetTokenPairTTL 设置默认管理器访问令牌和刷新令牌的有效期，小于等于0的值保持不变
+func SetTokenPairTTL(access, refresh time.Duration) {
+	defaultManager.Apply(WithTokenPairTTL(access, refresh))
+}
+
+// newTokenID 生成token唯一ID（jti）
+func newTokenID() string {
+	bytes := make([]byte, 16)
+	rand.Read(bytes)
+	return hex.EncodeToString(bytes)
+}
+
+// familyRevocationID 令牌族在撤销存储中的ID
+func familyRevocationID(familyID string) string {
+	return "family:" + familyID
+}
+
+// GenerateTokenPair 使用默认管理器签发令牌对
+func GenerateTokenPair(user UserInfo) (*TokenPair, error) {
+	return defaultManager.GenerateTokenPair(user)
+}
+
+// RefreshTokenPair 使用默认管理器轮换令牌对
+func RefreshTokenPair(refreshToken string) (*TokenPair, error) {
+	return defaultManager.RefreshTokenPair(refreshToken)
+}
+
+// RevokeToken 使用默认管理器撤销单个token
+func RevokeToken(tokenString string) error {
+	return defaultManager.RevokeToken(tokenString)
+}
+
+// RevokeTokenFamily 使用默认管理器撤销token所在的令牌族
+func RevokeTokenFamily(tokenString string) error {
+	return defaultManager.RevokeTokenFamily(tokenString)
+}
+
+// GenerateTokenPair 签发访问令牌和刷新令牌，两者属于同一个新的令牌族
+func (m *Manager) GenerateTokenPair(user UserInfo) (*TokenPair, error) {
+	return m.issueTokenPair(user, newTokenID())
+}
+
+// issueTokenPair 在指定令牌族下签发一对令牌
+func (m *Manager) issueTokenPair(user UserInfo, familyID string) (*TokenPair, error) {
+	s, err := m.currentSigner()
+	if err != nil {
+		return nil, err
+	}
+
+	_, accessTTL, refreshTTL := m.ttls()
+	now := m.now()
+	pair := &TokenPair{
+		TokenType:        "Bearer",
+		AccessExpiresAt:  now.Add(accessTTL),
+		RefreshExpiresAt: now.Add(refreshTTL),
+	}
+
+	access := CustomClaims{UserInfo: user, TokenType: TokenTypeAccess, FamilyID: familyID}
+	m.fillRegistered(&access.RegisteredClaims, user.UserId, accessTTL)
+	pair.AccessToken, err = s.Sign(access)
+	if err != nil {
+		return nil, err
+	}
+
+	refresh := CustomClaims{UserInfo: user, TokenType: TokenTypeRefresh, FamilyID: familyID}
+	m.fillRegistered(&refresh.RegisteredClaims, user.UserId, refreshTTL)
+	pair.RefreshToken, err = s.Sign(refresh)
+	if err != nil {
+		return nil, err
+	}
+	return pair, nil
+}
+
+// RefreshTokenPair 使用刷新令牌换取新的令牌对，旧刷新令牌随即失效（轮换）
+// 已使用过的刷新令牌再次出现视为被盗用，整个令牌族（含已签发的访问令牌）都会被撤销
+// 未设置撤销存储时只做轮换，无法检测重用
+func (m *Manager) RefreshTokenPair(refreshToken string) (*TokenPair, error) {
+	ctx := context.Background()
+	claims, err := m.parseClaims(refreshToken)
+	if err != nil {
+		return nil, err
+	}
+	if claims.TokenType != TokenTypeRefresh || claims.FamilyID == "" || claims.ExpiresAt == nil {
+		return nil, errno.ErrTokenInvalid
+	}
+
+	if store := m.revocationStore(); store != nil {
+		if err := m.checkRevoked(ctx, "", claims.FamilyID); err != nil {
+			return nil, err
+		}
+
+		// 过期后在时钟偏差内仍可使用，撤销记录要保留到那时才能检测重用
+		used, err := store.Revoke(ctx, claims.ID, m.revokeUntil(claims.ExpiresAt.Time))
+		if err != nil {
+			logger.Errorf(ctx, "撤销刷新令牌失败: %v", err)
+			return nil, errno.New(errno.ErrTokenValidate, err)
+		}
+		if used {
+			logger.Warnf(ctx, "检测到刷新令牌重用，撤销令牌族: user=%s family=%s", claims.UserId, claims.FamilyID)
+			_, _, refreshTTL := m.ttls()
+			if _, err := store.Revoke(ctx, familyRevocationID(claims.FamilyID), m.now().Add(refreshTTL)); err != nil {
+				logger.Errorf(ctx, "撤销令牌族失败: %v", err)
+			}
+			return nil, errno.ErrTokenRevoked
+		}
+	}
+
+	return m.issueTokenPair(claims.UserInfo, claims.FamilyID)
+}
+
+// RevokeToken 撤销单个token（访问令牌或刷新令牌），需要先设置撤销存储
+func (m *Manager) RevokeToken(tokenString string) error {
+	store, claims, err := m.parseForRevoke(tokenString)
+	if err != nil {
+		return err
+	}
+	_, err = store.Revoke(context.Background(), claims.ID, m.revokeUntil(claims.ExpiresAt.Time))
+	return err
+}
+
+// RevokeTokenFamily 撤销token所在的整个令牌族，用于退出登录
+func (m *Manager) RevokeTokenFamily(tokenString string) error {
+	store, claims, err := m.parseForRevoke(tokenString)
+	if err != nil {
+		return err
+	}
+	if claims.FamilyID == "" {
+		_, err = store.Revoke(context.Background(), claims.ID, m.revokeUntil(claims.ExpiresAt.Time))
+		return err
+	}
+	_, _, refreshTTL := m.ttls()
+	_, err = store.Revoke(context.Background(), familyRevocationID(claims.FamilyID), m.now().Add(refreshTTL))
+	return err
+}
+
+// RevokeID 按ID撤销token，familyID 不为空时撤销整个令牌族，用于只保存了ID的场景（如会话管理）
+func (m *Manager) RevokeID(ctx context.Context, jti, familyID string, expiresAt time.Time) error {
+	store := m.revocationStore()
+	if store == nil {
+		return errors.New("未设置撤销存储，请先调用 UseRevocationStore")
+	}
+	if familyID != "" {
+		_, _, refreshTTL := m.ttls()
+		_, err := store.Revoke(ctx, familyRevocationID(familyID), m.now().Add(refreshTTL))
+		return err
+	}
+	if jti == "" {
+		return errno.ErrTokenInvalid
+	}
+	_, err := store.Revoke(ctx, jti, m.revokeUntil(expiresAt))
+	return err
+}
+
+// revokeUntil 撤销记录的保留时间，token在 exp 之后的时钟偏差内仍被接受
+func (m *Manager) revokeUntil(expiresAt time.Time) time.Time {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return expiresAt.Add(m.leeway)
+}
+
+// parseForRevoke 校验签名并确认可以撤销
+func (m *Manager) parseForRevoke(tokenString string) (RevocationStore, *CustomClaims, error) {
+	store := m.revocationStore()
+	if store == nil {
+		return nil, nil, errors.New("未设置撤销存储，请先调用 UseRevocationStore")
+	}
+	claims, err := m.parseClaims(tokenString)
+	if err != nil {
+		return nil, nil, err
+	}
+	if claims.ID == "" || claims.ExpiresAt == nil {
+		return nil, nil, errno.ErrTokenInvalid
+	}
+	return store, claims, nil
+}
@@ -0,0 +1,87 @@
+package jwt
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Dev-Umb/go-pkg/errno"
+)
+
+func TestTokenPairRotation(t *testing.T) {
+	InitJwtSecret("test-secret")
+	UseRevocationStore(NewMemoryRevocationStore())
+	defer UseRevocationStore(nil)
+
+	pair, err := GenerateTokenPair(UserInfo{UserId: "u1"})
+	if err != nil {
+		t.Fatalf("generate pair: %v", err)
+	}
+	if _, err := ParseToken(pair.AccessToken); err != nil {
+		t.Fatalf("access token should be valid: %v", err)
+	}
+	if _, err := ParseToken(pair.RefreshToken); err == nil {
+		t.Fatalf("refresh token must not be accepted as access token")
+	}
+	verifier := NewVerifier(VerifyKey{Algorithm: "HS256", Key: []byte("test-secret")})
+	if _, err := ParseTokenWith(verifier, pair.RefreshToken); err != errno.ErrTokenInvalid {
+		t.Fatalf("ParseTokenWith accepted refresh token, err = %v", err)
+	}
+	if _, err := ParseTokenWith(verifier, pair.AccessToken); err != nil {
+		t.Fatalf("ParseTokenWith rejected access token: %v", err)
+	}
+
+	rotated, err := RefreshTokenPair(pair.RefreshToken)
+	if err != nil {
+		t.Fatalf("refresh: %v", err)
+	}
+
+	// 重用旧的刷新令牌会撤销整个令牌族
+	if _, err := RefreshTokenPair(pair.RefreshToken); err != errno.ErrTokenRevoked {
+		t.Fatalf("expected reuse detection, got %v", err)
+	}
+	if _, err := ParseToken(rotated.AccessToken); TokenErrno(err) != errno.ErrTokenRevoked {
+		t.Fatalf("expected rotated access token to be revoked, got %v", err)
+	}
+	if _, err := RefreshTokenPair(rotated.RefreshToken); err != errno.ErrTokenRevoked {
+		t.Fatalf("expected rotated refresh token to be revoked, got %v", err)
+	}
+}
+
+func TestRevokeToken(t *testing.T) {
+	InitJwtSecret("test-secret")
+	UseRevocationStore(NewMemoryRevocationStore())
+	defer UseRevocationStore(nil)
+
+	token, err := GenerateToken(UserInfo{UserId: "u1"})
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	if err := RevokeToken(token); err != nil {
+		t.Fatalf("revoke: %v", err)
+	}
+	if _, err := ParseToken(token); err != errno.ErrTokenRevoked {
+		t.Fatalf("expected revoked, got %v", err)
+	}
+}
+
+func TestRefreshReuseWithinLeeway(t *testing.T) {
+	store := NewMemoryRevocationStore()
+	m := NewManager(WithSecret("test-secret"), WithRevocationStore(store),
+		WithTokenPairTTL(time.Minute, time.Minute), WithLeeway(time.Hour))
+	pair, err := m.GenerateTokenPair(UserInfo{UserId: "u1"})
+	if err != nil {
+		t.Fatalf("generate pair: %v", err)
+	}
+
+	// 刷新令牌已过期，但仍在时钟偏差内
+	later := time.Now().Add(2 * time.Minute)
+	m.now = func() time.Time { return later }
+	store.now = func() time.Time { return later }
+
+	if _, err := m.RefreshTokenPair(pair.RefreshToken); err != nil {
+		t.Fatalf("refresh within leeway: %v", err)
+	}
+	if _, err := m.RefreshTokenPair(pair.RefreshToken); err != errno.ErrTokenRevoked {
+		t.Fatalf("expected reuse within leeway to be detected, got %v", err)
+	}
+}
@@ -0,0 +1,69 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// clusterRedis 模拟 Redis Cluster：多key命令的key不在同一个slot时返回 CROSSSLOT
+type clusterRedis struct {
+	redis.UniversalClient
+	keys map[string]bool
+}
+
+// slotTag 返回决定slot的部分，有哈希标签时只取标签内容
+func slotTag(key string) string {
+	if start := strings.Index(key, "{"); start >= 0 {
+		if end := strings.Index(key[start+1:], "}"); end > 0 {
+			return key[start+1 : start+1+end]
+		}
+	}
+	return key
+}
+
+func (c *clusterRedis) Exists(ctx context.Context, keys ...string) *redis.IntCmd {
+	cmd := redis.NewIntCmd(ctx)
+	for _, key := range keys[1:] {
+		if slotTag(key) != slotTag(keys[0]) {
+			cmd.SetErr(errors.New("CROSSSLOT Keys in request don't hash to the same slot"))
+			return cmd
+		}
+	}
+	var count int64
+	for _, key := range keys {
+		if c.keys[key] {
+			count++
+		}
+	}
+	cmd.SetVal(count)
+	return cmd
+}
+
+func (c *clusterRedis) SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) *redis.BoolCmd {
+	cmd := redis.NewBoolCmd(ctx)
+	cmd.SetVal(!c.keys[key])
+	c.keys[key] = true
+	return cmd
+}
+
+func TestRedisRevocationStoreCluster(t *testing.T) {
+	ctx := context.Background()
+	store := NewRedisRevocationStore(&clusterRedis{keys: map[string]bool{}}, "")
+
+	revoked, err := store.IsRevoked(ctx, "jti-1", "family-1")
+	if err != nil || revoked {
+		t.Fatalf("IsRevoked = %v, %v, want false, nil", revoked, err)
+	}
+	if _, err := store.Revoke(ctx, "family-1", time.Now().Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	revoked, err = store.IsRevoked(ctx, "jti-1", "family-1")
+	if err != nil || !revoked {
+		t.Fatalf("IsRevoked = %v, %v, want true, nil", revoked, err)
+	}
+}
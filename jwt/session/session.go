@@ -0,0 +1,250 @@
+// Package session 多端登录会话管理
+// 记录每个签发token的 jti、平台和设备，按平台限制同时在线的会话数，
+// 超出限制时撤销最早的会话并通过 user-notification 发布踢下线事件
+package session
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Dev-Umb/go-pkg/jwt"
+	"github.com/Dev-Umb/go-pkg/logger"
+	notification "github.com/Dev-Umb/go-pkg/user-notification"
+)
+
+// Session 登录会话
+type Session struct {
+	ID        string    `json:"id"`                  // 会话ID，签发令牌对时为令牌族ID，否则为token的jti
+	TokenID   string    `json:"jti"`                 // 签发时的token jti
+	FamilyID  string    `json:"fid,omitempty"`       // 令牌族ID
+	OpenId    string    `json:"open_id"`             // 用户 OpenId，为空时使用 UserId
+	UserId    string    `json:"user_id"`             // 用户ID
+	Platform  string    `json:"platform"`            // 平台代码
+	Device    string    `json:"device,omitempty"`    // 设备标识
+	IssuedAt  time.Time `json:"issued_at"`           // 签发时间
+	ExpiresAt time.Time `json:"expires_at"`          // 过期时间
+	ClientIP  string    `json:"client_ip,omitempty"` // 登录IP
+}
+
+// Notifier 踢下线通知，notification.Client 实现了该接口
+type Notifier interface {
+	PublishKickOff(openId, platformCode string, reason ...string) error
+}
+
+// DefaultKickOffReason 超出会话上限时的踢下线原因
+const DefaultKickOffReason = "账号在其他设备登录，当前设备已下线"
+
+// Manager 会话管理器
+type Manager struct {
+	store        Store
+	jwt          *jwt.Manager
+	notifier     Notifier
+	limits       map[string]int
+	defaultLimit int
+	reason       string
+}
+
+// Option 会话管理器配置选项
+type Option func(*Manager)
+
+// WithJWTManager 设置签发和撤销token使用的JWT管理器，默认为 jwt.DefaultManager()
+// JWT管理器需要设置撤销存储，否则被踢出的token在过期前仍然有效
+func WithJWTManager(m *jwt.Manager) Option {
+	return func(s *Manager) {
+		s.jwt = m
+	}
+}
+
+// WithNotifier 设置踢下线通知，默认使用已初始化的 user-notification 全局客户端
+func WithNotifier(n Notifier) Option {
+	return func(s *Manager) {
+		s.notifier = n
+	}
+}
+
+// WithMaxSessions 设置平台同时在线的最大会话数，小于等于0表示不限制
+func WithMaxSessions(platform string, max int) Option {
+	return func(s *Manager) {
+		s.limits[platform] = max
+	}
+}
+
+// WithDefaultMaxSessions 设置未单独配置的平台的最大会话数，默认不限制
+func WithDefaultMaxSessions(max int) Option {
+	return func(s *Manager) {
+		s.defaultLimit = max
+	}
+}
+
+// WithKickOffReason 设置超出会话上限时的踢下线原因
+func WithKickOffReason(reason string) Option {
+	return func(s *Manager) {
+		s.reason = reason
+	}
+}
+
+// NewManager 创建会话管理器
+// JWT管理器必须已设置撤销存储，否则踢出的会话无法使token失效
+func NewManager(store Store, opts ...Option) (*Manager, error) {
+	m := &Manager{store: store, limits: make(map[string]int), reason: DefaultKickOffReason}
+	for _, opt := range opts {
+		opt(m)
+	}
+	if !m.jwtManager().HasRevocationStore() {
+		return nil, errors.New("JWT管理器未设置撤销存储，请先调用 jwt.UseRevocationStore 或 jwt.WithRevocationStore")
+	}
+	return m, nil
+}
+
+func (m *Manager) jwtManager() *jwt.Manager {
+	if m.jwt != nil {
+		return m.jwt
+	}
+	return jwt.DefaultManager()
+}
+
+// maxSessions 平台的最大会话数
+func (m *Manager) maxSessions(platform string) int {
+	if max, ok := m.limits[platform]; ok {
+		return max
+	}
+	return m.defaultLimit
+}
+
+// Issue 签发token并记录会话
+func (m *Manager) Issue(ctx context.Context, user jwt.UserInfo, platform, device string) (string, error) {
+	jm := m.jwtManager()
+	token, err := jm.GenerateToken(user)
+	if err != nil {
+		return "", err
+	}
+	claims, err := jm.ParseToken(token)
+	if err != nil {
+		return "", err
+	}
+
+	s := newSession(claims, platform, device)
+	if err := m.Record(ctx, s); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// IssuePair 签发令牌对并记录会话，会话ID为令牌族ID，刷新令牌轮换不会产生新会话
+func (m *Manager) IssuePair(ctx context.Context, user jwt.UserInfo, platform, device string) (*jwt.TokenPair, error) {
+	jm := m.jwtManager()
+	pair, err := jm.GenerateTokenPair(user)
+	if err != nil {
+		return nil, err
+	}
+	claims, err := jm.ParseToken(pair.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	s := newSession(claims, platform, device)
+	s.ExpiresAt = pair.RefreshExpiresAt
+	if err := m.Record(ctx, s); err != nil {
+		return nil, err
+	}
+	return pair, nil
+}
+
+// newSession 根据声明创建会话
+func newSession(claims *jwt.CustomClaims, platform, device string) Session {
+	s := Session{
+		ID:       claims.ID,
+		TokenID:  claims.ID,
+		FamilyID: claims.FamilyID,
+		OpenId:   claims.OpenId,
+		UserId:   claims.UserId,
+		Platform: platform,
+		Device:   device,
+		IssuedAt: time.Now(), // token的 iat 只精确到秒，无法区分同一秒内的登录
+	}
+	if s.FamilyID != "" {
+		s.ID = s.FamilyID
+	}
+	if s.OpenId == "" {
+		s.OpenId = s.UserId
+	}
+	if claims.ExpiresAt != nil {
+		s.ExpiresAt = claims.ExpiresAt.Time
+	}
+	return s
+}
+
+// Record 记录会话，超出平台会话上限时踢出最早的会话
+// 清理或踢出失败时返回错误，此时新会话已经保存
+func (m *Manager) Record(ctx context.Context, s Session) error {
+	if s.ID == "" || s.OpenId == "" {
+		return errors.New("会话ID和用户OpenId不能为空")
+	}
+	if s.IssuedAt.IsZero() {
+		s.IssuedAt = time.Now()
+	}
+	if err := m.store.Add(ctx, s); err != nil {
+		logger.Errorf(ctx, "保存会话失败: %v", err)
+		return err
+	}
+
+	max := m.maxSessions(s.Platform)
+	if max <= 0 {
+		return nil
+	}
+	evicted, err := m.store.Trim(ctx, s.OpenId, s.Platform, max)
+	if err != nil {
+		logger.Errorf(ctx, "清理超出上限的会话失败: %v", err)
+	}
+	errs := []error{err}
+	for _, old := range evicted {
+		errs = append(errs, m.kickOff(ctx, old, m.reason))
+	}
+	return errors.Join(errs...)
+}
+
+// List 列出用户在平台上的会话，按签发时间从早到晚排序
+func (m *Manager) List(ctx context.Context, openId, platform string) ([]Session, error) {
+	return m.store.List(ctx, openId, platform)
+}
+
+// Logout 用户主动退出，撤销会话对应的token，不发布踢下线事件
+func (m *Manager) Logout(ctx context.Context, s Session) error {
+	if err := m.store.Remove(ctx, s.OpenId, s.Platform, s.ID); err != nil {
+		return err
+	}
+	return m.jwtManager().RevokeID(ctx, s.TokenID, s.FamilyID, s.ExpiresAt)
+}
+
+// Kick 踢出指定会话并发布踢下线事件
+func (m *Manager) Kick(ctx context.Context, s Session, reason string) error {
+	if err := m.store.Remove(ctx, s.OpenId, s.Platform, s.ID); err != nil {
+		return err
+	}
+	return m.kickOff(ctx, s, reason)
+}
+
+// kickOff 撤销会话token并发布踢下线事件
+// 会话已从存储中移除，撤销失败时仍发布事件，让客户端主动下线
+func (m *Manager) kickOff(ctx context.Context, s Session, reason string) error {
+	revokeErr := m.jwtManager().RevokeID(ctx, s.TokenID, s.FamilyID, s.ExpiresAt)
+	if revokeErr != nil {
+		logger.Errorf(ctx, "撤销会话token失败: open_id=%s platform=%s session=%s: %v", s.OpenId, s.Platform, s.ID, revokeErr)
+	} else {
+		logger.Infof(ctx, "会话已踢下线: open_id=%s platform=%s device=%s session=%s", s.OpenId, s.Platform, s.Device, s.ID)
+	}
+
+	notifier := m.notifier
+	if notifier == nil {
+		if !notification.IsGlobalClientInitialized() {
+			return revokeErr
+		}
+		notifier = notification.GetGlobalClient()
+	}
+	if err := notifier.PublishKickOff(s.OpenId, s.Platform, reason); err != nil {
+		logger.Errorf(ctx, "发布踢下线事件失败: open_id=%s platform=%s: %v", s.OpenId, s.Platform, err)
+		return errors.Join(revokeErr, err)
+	}
+	return revokeErr
+}
@@ -0,0 +1,118 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Dev-Umb/go-pkg/errno"
+	"github.com/Dev-Umb/go-pkg/jwt"
+)
+
+type kickOffRecorder struct {
+	events []string
+}
+
+func (r *kickOffRecorder) PublishKickOff(openId, platformCode string, reason ...string) error {
+	r.events = append(r.events, openId+"/"+platformCode)
+	return nil
+}
+
+func TestMaxSessionsKickOff(t *testing.T) {
+	ctx := context.Background()
+	jm := jwt.NewManager(jwt.WithSecret("test-secret"), jwt.WithRevocationStore(jwt.NewMemoryRevocationStore()))
+	notifier := &kickOffRecorder{}
+	m, err := NewManager(NewMemoryStore(), WithJWTManager(jm), WithNotifier(notifier), WithMaxSessions("app", 1))
+	if err != nil {
+		t.Fatalf("new manager: %v", err)
+	}
+
+	user := jwt.UserInfo{OpenId: "o1", UserId: "u1"}
+	first, err := m.IssuePair(ctx, user, "app", "iphone")
+	if err != nil {
+		t.Fatalf("issue first: %v", err)
+	}
+	second, err := m.IssuePair(ctx, user, "app", "ipad")
+	if err != nil {
+		t.Fatalf("issue second: %v", err)
+	}
+	if _, err := m.Issue(ctx, user, "web", "chrome"); err != nil {
+		t.Fatalf("issue web: %v", err)
+	}
+
+	if _, err := jm.ParseToken(first.AccessToken); jwt.TokenErrno(err) != errno.ErrTokenRevoked {
+		t.Fatalf("expected oldest session to be revoked, got %v", err)
+	}
+	if _, err := jm.RefreshTokenPair(first.RefreshToken); err != errno.ErrTokenRevoked {
+		t.Fatalf("expected oldest refresh token to be revoked, got %v", err)
+	}
+	if _, err := jm.ParseToken(second.AccessToken); err != nil {
+		t.Fatalf("latest session should be valid: %v", err)
+	}
+	if len(notifier.events) != 1 || notifier.events[0] != "o1/app" {
+		t.Fatalf("unexpected kick-off events: %v", notifier.events)
+	}
+
+	sessions, _ := m.List(ctx, "o1", "app")
+	if len(sessions) != 1 || sessions[0].Device != "ipad" {
+		t.Fatalf("unexpected sessions: %+v", sessions)
+	}
+}
+
+// failingRevocationStore 撤销总是失败
+type failingRevocationStore struct{}
+
+func (failingRevocationStore) Revoke(ctx context.Context, id string, expiresAt time.Time) (bool, error) {
+	return false, errors.New("redis unavailable")
+}
+
+func (failingRevocationStore) IsRevoked(ctx context.Context, ids ...string) (bool, error) {
+	return false, nil
+}
+
+func TestKickOffWhenRevokeFails(t *testing.T) {
+	if _, err := NewManager(NewMemoryStore(), WithJWTManager(jwt.NewManager(jwt.WithSecret("test-secret")))); err == nil {
+		t.Fatalf("expected error for JWT manager without revocation store")
+	}
+
+	ctx := context.Background()
+	jm := jwt.NewManager(jwt.WithSecret("test-secret"), jwt.WithRevocationStore(failingRevocationStore{}))
+	notifier := &kickOffRecorder{}
+	m, err := NewManager(NewMemoryStore(), WithJWTManager(jm), WithNotifier(notifier), WithMaxSessions("app", 1))
+	if err != nil {
+		t.Fatalf("new manager: %v", err)
+	}
+
+	user := jwt.UserInfo{OpenId: "o1", UserId: "u1"}
+	if _, err := m.Issue(ctx, user, "app", "iphone"); err != nil {
+		t.Fatalf("issue first: %v", err)
+	}
+	// 撤销失败时仍发布踢下线事件，并返回错误
+	if _, err := m.Issue(ctx, user, "app", "ipad"); err == nil {
+		t.Fatalf("expected revoke error to be returned")
+	}
+	if len(notifier.events) != 1 {
+		t.Fatalf("expected kick-off event despite revoke failure: %v", notifier.events)
+	}
+}
+
+func TestRedisStoreKeysShareSlot(t *testing.T) {
+	r := NewRedisStore(nil, "")
+	tag := func(key string) string {
+		start := strings.Index(key, "{")
+		end := strings.Index(key, "}")
+		if start < 0 || end <= start+1 {
+			t.Fatalf("key %q has no hash tag", key)
+		}
+		return key[start+1 : end]
+	}
+	set := tag(r.setKey("open-1", "ios"))
+	if data := tag(r.dataKey("open-1", "ios", "sid-1")); data != set {
+		t.Fatalf("data key tag %q != set key tag %q", data, set)
+	}
+	if other := tag(r.setKey("open-2", "ios")); other == set {
+		t.Fatalf("different groups share hash tag %q", other)
+	}
+}
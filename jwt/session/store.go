@@ -0,0 +1,250 @@
+package session
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// Store 会话存储，会话按 OpenId + 平台分组，组内按签发时间排序
+type Store interface {
+	// Add 保存会话，过期时间与会话的 ExpiresAt 一致
+	Add(ctx context.Context, s Session) error
+	// Trim 只保留最新的 keep 个会话，移除并返回更早的会话
+	Trim(ctx context.Context, openId, platform string, keep int) ([]Session, error)
+	// Remove 移除会话
+	Remove(ctx context.Context, openId, platform, id string) error
+	// List 按签发时间从早到晚列出未过期的会话
+	List(ctx context.Context, openId, platform string) ([]Session, error)
+}
+
+// MemoryStore 进程内会话存储，适用于单实例部署和测试
+type MemoryStore struct {
+	mu       sync.Mutex
+	sessions map[string][]Session
+	now      func() time.Time
+}
+
+// NewMemoryStore 创建进程内会话存储
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{sessions: make(map[string][]Session), now: time.Now}
+}
+
+// Add 保存会话
+func (m *MemoryStore) Add(ctx context.Context, s Session) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := groupKey(s.OpenId, s.Platform)
+	list := m.aliveLocked(key)
+	for i := range list {
+		if list[i].ID == s.ID {
+			list = append(list[:i], list[i+1:]...)
+			break
+		}
+	}
+	list = append(list, s)
+	sort.SliceStable(list, func(i, j int) bool { return list[i].IssuedAt.Before(list[j].IssuedAt) })
+	m.sessions[key] = list
+	return nil
+}
+
+// Trim 只保留最新的 keep 个会话
+func (m *MemoryStore) Trim(ctx context.Context, openId, platform string, keep int) ([]Session, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := groupKey(openId, platform)
+	list := m.aliveLocked(key)
+	if len(list) <= keep {
+		return nil, nil
+	}
+	n := len(list) - keep
+	evicted := append([]Session(nil), list[:n]...)
+	m.sessions[key] = append([]Session(nil), list[n:]...)
+	return evicted, nil
+}
+
+// Remove 移除会话
+func (m *MemoryStore) Remove(ctx context.Context, openId, platform, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := groupKey(openId, platform)
+	list := m.sessions[key]
+	for i := range list {
+		if list[i].ID == id {
+			m.sessions[key] = append(list[:i], list[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+// List 列出未过期的会话
+func (m *MemoryStore) List(ctx context.Context, openId, platform string) ([]Session, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Session(nil), m.aliveLocked(groupKey(openId, platform))...), nil
+}
+
+// aliveLocked 清理已过期的会话，调用方需持有锁
+func (m *MemoryStore) aliveLocked(key string) []Session {
+	now := m.now()
+	list := m.sessions[key][:0]
+	for _, s := range m.sessions[key] {
+		if s.ExpiresAt.IsZero() || now.Before(s.ExpiresAt) {
+			list = append(list, s)
+		}
+	}
+	m.sessions[key] = list
+	return list
+}
+
+// RedisStore 基于Redis的会话存储，适用于多实例部署
+// 每组会话使用一个有序集合（成员为会话ID，分数为签发时间），会话详情单独保存并随token过期
+// 同组的key使用相同的哈希标签，在 Redis Cluster 下落在同一个slot，可以在事务和脚本中一起操作
+type RedisStore struct {
+	rdb    redis.UniversalClient
+	prefix string
+}
+
+// NewRedisStore 创建Redis会话存储，prefix 为空时使用 "jwt:session:"
+func NewRedisStore(rdb redis.UniversalClient, prefix string) *RedisStore {
+	if prefix == "" {
+		prefix = "jwt:session:"
+	}
+	return &RedisStore{rdb: rdb, prefix: prefix}
+}
+
+func (r *RedisStore) setKey(openId, platform string) string {
+	return r.prefix + "{" + groupKey(openId, platform) + "}:set"
+}
+
+func (r *RedisStore) dataKey(openId, platform, id string) string {
+	return r.prefix + "{" + groupKey(openId, platform) + "}:data:" + id
+}
+
+// addScript 保存会话详情并加入集合，集合的过期时间不短于组内最晚过期的会话
+// KEYS[1] 详情，KEYS[2] 集合；ARGV[1] 详情，ARGV[2] 签发时间，ARGV[3] 会话ID，ARGV[4] 过期毫秒数，0 表示不过期
+// 集合已存在且没有过期时间（含不过期的会话）时保持不过期
+var addScript = redis.NewScript(`
+local ttl = tonumber(ARGV[4])
+local existed = redis.call('EXISTS', KEYS[2])
+if ttl > 0 then
+	redis.call('SET', KEYS[1], ARGV[1], 'PX', ttl)
+else
+	redis.call('SET', KEYS[1], ARGV[1])
+end
+redis.call('ZADD', KEYS[2], ARGV[2], ARGV[3])
+if ttl <= 0 then
+	redis.call('PERSIST', KEYS[2])
+elseif existed == 0 then
+	redis.call('PEXPIRE', KEYS[2], ttl)
+else
+	local current = redis.call('PTTL', KEYS[2])
+	if current >= 0 and current < ttl then
+		redis.call('PEXPIRE', KEYS[2], ttl)
+	end
+end
+return 1
+`)
+
+// Add 保存会话
+func (r *RedisStore) Add(ctx context.Context, s Session) error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	ttl := time.Until(s.ExpiresAt)
+	if s.ExpiresAt.IsZero() {
+		ttl = 0
+	} else if ttl <= 0 {
+		return nil
+	}
+
+	ms := ttl.Milliseconds()
+	if ttl > 0 && ms == 0 {
+		ms = 1
+	}
+	keys := []string{r.dataKey(s.OpenId, s.Platform, s.ID), r.setKey(s.OpenId, s.Platform)}
+	return addScript.Run(ctx, r.rdb, keys, data, s.IssuedAt.UnixNano(), s.ID, ms).Err()
+}
+
+// Trim 只保留最新的 keep 个会话，ZREM 成功的实例负责返回被移除的会话，避免并发登录重复踢出
+func (r *RedisStore) Trim(ctx context.Context, openId, platform string, keep int) ([]Session, error) {
+	list, err := r.List(ctx, openId, platform)
+	if err != nil || len(list) <= keep {
+		return nil, err
+	}
+
+	setKey := r.setKey(openId, platform)
+	evicted := make([]Session, 0, len(list)-keep)
+	for _, s := range list[:len(list)-keep] {
+		removed, err := r.rdb.ZRem(ctx, setKey, s.ID).Result()
+		if err != nil {
+			return evicted, err
+		}
+		if removed == 0 {
+			continue
+		}
+		r.rdb.Del(ctx, r.dataKey(openId, platform, s.ID))
+		evicted = append(evicted, s)
+	}
+	return evicted, nil
+}
+
+// Remove 移除会话
+func (r *RedisStore) Remove(ctx context.Context, openId, platform, id string) error {
+	pipe := r.rdb.TxPipeline()
+	pipe.ZRem(ctx, r.setKey(openId, platform), id)
+	pipe.Del(ctx, r.dataKey(openId, platform, id))
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// List 列出未过期的会话，顺带清理详情已过期的成员
+func (r *RedisStore) List(ctx context.Context, openId, platform string) ([]Session, error) {
+	setKey := r.setKey(openId, platform)
+	ids, err := r.rdb.ZRange(ctx, setKey, 0, -1).Result()
+	if err != nil || len(ids) == 0 {
+		return nil, err
+	}
+
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = r.dataKey(openId, platform, id)
+	}
+	values, err := r.rdb.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]Session, 0, len(ids))
+	var expired []interface{}
+	for i, v := range values {
+		data, ok := v.(string)
+		if !ok {
+			expired = append(expired, ids[i])
+			continue
+		}
+		var s Session
+		if err := json.Unmarshal([]byte(data), &s); err != nil {
+			expired = append(expired, ids[i])
+			continue
+		}
+		list = append(list, s)
+	}
+	if len(expired) > 0 {
+		r.rdb.ZRem(ctx, setKey, expired...)
+	}
+	return list, nil
+}
+
+func groupKey(openId, platform string) string {
+	return openId + ":" + platform
+}
@@ -0,0 +1,177 @@
+package jwt
+
+import (
+	"crypto"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// Signer token签名器
+type Signer interface {
+	// Sign 签名声明，返回token字符串
+	Sign(claims jwt.Claims) (string, error)
+}
+
+// Verifier token验证器
+type Verifier interface {
+	// Keyfunc 根据token头部（alg、kid）返回验证密钥，需要拒绝与密钥不匹配的算法
+	Keyfunc(token *jwt.Token) (interface{}, error)
+}
+
+// VerifyKey 验证密钥
+type VerifyKey struct {
+	KeyID     string
+	Algorithm string
+	Key       interface{} // []byte、*rsa.PublicKey、*ecdsa.PublicKey 或 ed25519.PublicKey
+}
+
+// KeySigner 使用单个密钥签名的签名器
+type KeySigner struct {
+	method jwt.SigningMethod
+	key    interface{}
+	kid    string
+}
+
+// NewSigner 创建签名器，算法根据密钥类型推断（见 AlgorithmForKey）
+// key 可以是 HMAC 密钥 []byte，或 ParsePrivateKeyPEM 返回的私钥
+func NewSigner(key interface{}, kid string) (*KeySigner, error) {
+	alg, err := AlgorithmForKey(key)
+	if err != nil {
+		return nil, err
+	}
+	return NewSignerWithAlgorithm(alg, key, kid)
+}
+
+// NewSignerWithAlgorithm 使用指定算法创建签名器
+func NewSignerWithAlgorithm(alg string, key interface{}, kid string) (*KeySigner, error) {
+	method := jwt.GetSigningMethod(alg)
+	if method == nil {
+		return nil, fmt.Errorf("不支持的签名算法: %s", alg)
+	}
+	if alg != "HS256" && alg != "HS384" && alg != "HS512" {
+		if _, ok := key.(crypto.Signer); !ok {
+			return nil, fmt.Errorf("算法 %s 需要私钥，实际为: %T", alg, key)
+		}
+	}
+	return &KeySigner{method: method, key: key, kid: kid}, nil
+}
+
+// NewSignerFromFile 从PEM私钥文件创建签名器
+func NewSignerFromFile(path, kid string) (*KeySigner, error) {
+	key, err := LoadPrivateKeyFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewSigner(key, kid)
+}
+
+// Sign 签名声明
+func (s *KeySigner) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(s.method, claims)
+	if s.kid != "" {
+		token.Header["kid"] = s.kid
+	}
+	return token.SignedString(s.key)
+}
+
+// KeyID 密钥ID
+func (s *KeySigner) KeyID() string {
+	return s.kid
+}
+
+// Algorithm 签名算法
+func (s *KeySigner) Algorithm() string {
+	return s.method.Alg()
+}
+
+// VerifyKey 返回签名密钥对应的验证密钥（非对称算法为公钥）
+func (s *KeySigner) VerifyKey() VerifyKey {
+	return VerifyKey{KeyID: s.kid, Algorithm: s.method.Alg(), Key: publicKeyOf(s.key)}
+}
+
+// Verifier 返回可验证该签名器所签token的验证器
+func (s *KeySigner) Verifier() *KeyVerifier {
+	return NewVerifier(s.VerifyKey())
+}
+
+// KeyVerifier 基于一组已知密钥的验证器，按 kid 选择密钥
+type KeyVerifier struct {
+	mu   sync.RWMutex
+	keys map[string]VerifyKey
+}
+
+// NewVerifier 创建验证器
+func NewVerifier(keys ...VerifyKey) *KeyVerifier {
+	v := &KeyVerifier{keys: make(map[string]VerifyKey, len(keys))}
+	v.SetKeys(keys...)
+	return v
+}
+
+// NewVerifierFromFile 从PEM公钥文件创建验证器，算法根据密钥类型推断
+func NewVerifierFromFile(path, kid string) (*KeyVerifier, error) {
+	key, err := LoadPublicKeyFile(path)
+	if err != nil {
+		return nil, err
+	}
+	alg, err := AlgorithmForKey(key)
+	if err != nil {
+		return nil, err
+	}
+	return NewVerifier(VerifyKey{KeyID: kid, Algorithm: alg, Key: key}), nil
+}
+
+// SetKeys 替换验证密钥
+func (v *KeyVerifier) SetKeys(keys ...VerifyKey) {
+	m := make(map[string]VerifyKey, len(keys))
+	for _, k := range keys {
+		m[k.KeyID] = k
+	}
+	v.mu.Lock()
+	v.keys = m
+	v.mu.Unlock()
+}
+
+// Keys 返回当前所有验证密钥
+func (v *KeyVerifier) Keys() []VerifyKey {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	keys := make([]VerifyKey, 0, len(v.keys))
+	for _, k := range v.keys {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// Keyfunc 根据 kid 选择密钥并校验算法
+// token 未携带 kid 且只有一个密钥时使用该密钥
+func (v *KeyVerifier) Keyfunc(token *jwt.Token) (interface{}, error) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	return lookupKey(v.keys, token)
+}
+
+// JWKS 导出非对称公钥，HMAC 密钥不会被导出
+func (v *KeyVerifier) JWKS() (*JWKS, error) {
+	return NewJWKS(v.Keys()...)
+}
+
+// lookupKey 从密钥集合中查找token对应的密钥
+func lookupKey(keys map[string]VerifyKey, token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	key, ok := keys[kid]
+	if !ok && kid == "" && len(keys) == 1 {
+		for _, k := range keys {
+			key, ok = k, true
+		}
+	}
+	if !ok {
+		return nil, fmt.Errorf("未知的密钥ID: %q", kid)
+	}
+	if token.Method == nil || token.Method.Alg() != key.Algorithm {
+		return nil, errors.New("签名算法与密钥不匹配")
+	}
+	return key.Key, nil
+}
@@ -0,0 +1,48 @@
+package nacos_sdk
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/Dev-Umb/go-pkg/logger"
+	"github.com/Dev-Umb/go-pkg/util"
+)
+
+// advertiseIPEnv 指定注册地址的环境变量
+const advertiseIPEnv = "NACOS_ADVERTISE_IP"
+
+// AdvertiseIP 选择注册到Nacos的本机地址并记录选择原因
+// 依次为：AdvertiseIP 配置、NACOS_ADVERTISE_IP 环境变量、优先网卡、优先网段、第一个非虚拟网卡地址
+// 指定的地址无效时返回错误，不注册回退地址
+func (c *Client) AdvertiseIP() (string, error) {
+	policy := util.IPPolicy{
+		Override:   c.config.AdvertiseIP,
+		Interfaces: c.config.PreferredInterfaces,
+		CIDRs:      c.config.PreferredCIDRs,
+		IPv6:       c.config.IPv6,
+	}
+	source := "config"
+	if policy.Override == "" {
+		policy.Override = os.Getenv(advertiseIPEnv)
+		source = advertiseIPEnv
+	}
+
+	selection, err := util.SelectIP(policy)
+	if err != nil {
+		if selection.IP == "" {
+			return "", fmt.Errorf("选择注册地址失败 (来源: %s): %w", source, err)
+		}
+		logger.Errorf(context.Background(), "选择注册地址失败，使用 %s: %v", selection.IP, err)
+		return selection.IP, nil
+	}
+	switch selection.Reason {
+	case "override":
+		logger.Infof(context.Background(), "使用指定的注册地址 %s (来源: %s)", selection.IP, source)
+	case "fallback":
+		logger.Warnf(context.Background(), "没有可用的网卡地址，注册地址为 %s", selection.IP)
+	default:
+		logger.Infof(context.Background(), "选择注册地址 %s (网卡: %s, 原因: %s)", selection.IP, selection.Interface, selection.Reason)
+	}
+	return selection.IP, nil
+}
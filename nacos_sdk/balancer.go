@@ -0,0 +1,59 @@
+package nacos_sdk
+
+import (
+	"sync"
+
+	"google.golang.org/grpc/balancer"
+	"google.golang.org/grpc/balancer/base"
+)
+
+// WeightedBalancerName 按Nacos实例权重进行平滑加权轮询的负载均衡策略名
+const WeightedBalancerName = "nacos_weighted"
+
+func init() {
+	balancer.Register(base.NewBalancerBuilder(WeightedBalancerName, &weightedPickerBuilder{}, base.Config{HealthCheck: true}))
+}
+
+type weightedPickerBuilder struct{}
+
+// Build 实现 base.PickerBuilder
+func (*weightedPickerBuilder) Build(info base.PickerBuildInfo) balancer.Picker {
+	if len(info.ReadySCs) == 0 {
+		return base.NewErrPicker(balancer.ErrNoSubConnAvailable)
+	}
+	p := &weightedPicker{}
+	for sc, sci := range info.ReadySCs {
+		p.items = append(p.items, &weightedItem{subConn: sc, weight: InstanceWeight(sci.Address)})
+	}
+	return p
+}
+
+type weightedItem struct {
+	subConn balancer.SubConn
+	weight  int
+	current int
+}
+
+// weightedPicker 平滑加权轮询（与nginx相同），权重高的实例被选中的次数成比例增加且分布均匀
+type weightedPicker struct {
+	mu    sync.Mutex
+	items []*weightedItem
+}
+
+// Pick 实现 balancer.Picker
+func (p *weightedPicker) Pick(balancer.PickInfo) (balancer.PickResult, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	total := 0
+	var best *weightedItem
+	for _, item := range p.items {
+		item.current += item.weight
+		total += item.weight
+		if best == nil || item.current > best.current {
+			best = item
+		}
+	}
+	best.current -= total
+	return balancer.PickResult{SubConn: best.subConn}, nil
+}
@@ -0,0 +1,316 @@
+package nacos_sdk
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/Dev-Umb/go-pkg/logger"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigFormat 配置内容格式
+type ConfigFormat string
+
+const (
+	FormatJSON       ConfigFormat = "json"
+	FormatYAML       ConfigFormat = "yaml"
+	FormatTOML       ConfigFormat = "toml"
+	FormatProperties ConfigFormat = "properties" // 按 json 标签映射，键以 . 分隔表示嵌套
+)
+
+// Validator 配置类型可实现该接口做 validate 标签之外的校验
+type Validator interface {
+	Validate() error
+}
+
+var (
+	validateOnce sync.Once
+	validate     *validator.Validate
+)
+
+// FormatOf 根据 dataId 的扩展名推断配置格式，无法识别时为 JSON
+func FormatOf(dataId string) ConfigFormat {
+	switch strings.ToLower(strings.TrimPrefix(path.Ext(dataId), ".")) {
+	case "yaml", "yml":
+		return FormatYAML
+	case "toml":
+		return FormatTOML
+	case "properties":
+		return FormatProperties
+	default:
+		return FormatJSON
+	}
+}
+
+// Binding 绑定到Nacos配置的类型化配置
+// Get 返回当前生效的配置，配置变更时重新解析，校验通过后原子替换并通知监听者
+type Binding[T any] struct {
+	dataId string
+	group  string
+	format ConfigFormat
+
+	value atomic.Pointer[T]
+
+	mu        sync.Mutex // 保证变更按顺序生效和通知
+	listeners []func(old, new *T)
+}
+
+// Bind 读取配置并解析为 T，校验失败时返回错误；之后监听配置变更并热更新
+// format 为空时根据 dataId 扩展名推断
+func Bind[T any](dataId, group string, format ConfigFormat) (*Binding[T], error) {
+	return BindWith[T](DefaultClient(), dataId, group, format)
+}
+
+// BindWith 使用指定客户端绑定配置，见 Bind
+func BindWith[T any](c *Client, dataId, group string, format ConfigFormat) (*Binding[T], error) {
+	b := newBinding[T](dataId, group, format)
+
+	data, err := c.GetConfigValue(dataId, group)
+	if err != nil {
+		return nil, err
+	}
+	if err := b.Update(data); err != nil {
+		return nil, err
+	}
+
+	if err := c.ListenConfigChange(dataId, group, func(data string) {
+		if err := b.Update(data); err != nil {
+			logger.Errorf(context.Background(), "配置变更未生效 [%s:%s]: %v", group, dataId, err)
+		}
+	}); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+func newBinding[T any](dataId, group string, format ConfigFormat) *Binding[T] {
+	if format == "" {
+		format = FormatOf(dataId)
+	}
+	return &Binding[T]{dataId: dataId, group: group, format: format}
+}
+
+// Get 返回当前配置，调用方不应修改返回值
+func (b *Binding[T]) Get() *T {
+	return b.value.Load()
+}
+
+// OnChange 注册配置变更监听，old 为替换前的配置
+func (b *Binding[T]) OnChange(fn func(old, new *T)) {
+	b.mu.Lock()
+	b.listeners = append(b.listeners, fn)
+	b.mu.Unlock()
+}
+
+// Update 解析并校验配置内容，通过后替换当前配置并通知监听者，失败时保留原配置
+func (b *Binding[T]) Update(data string) error {
+	value, err := ParseConfig[T](data, b.format)
+	if err != nil {
+		return fmt.Errorf("解析配置失败 [%s:%s]: %w", b.group, b.dataId, err)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	old := b.value.Swap(value)
+	for _, fn := range b.listeners {
+		fn(old, value)
+	}
+	return nil
+}
+
+// ParseConfig 按格式解析配置内容并校验
+func ParseConfig[T any](data string, format ConfigFormat) (*T, error) {
+	value := new(T)
+	var err error
+	switch format {
+	case FormatJSON, "":
+		err = json.Unmarshal([]byte(data), value)
+	case FormatYAML:
+		err = yaml.Unmarshal([]byte(data), value)
+	case FormatTOML:
+		err = toml.Unmarshal([]byte(data), value)
+	case FormatProperties:
+		err = unmarshalProperties(data, value)
+	default:
+		err = fmt.Errorf("不支持的配置格式: %s", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := validateConfig(value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// validateConfig 执行 validate 标签校验和 Validator 接口校验
+func validateConfig(value interface{}) error {
+	if reflect.Indirect(reflect.ValueOf(value)).Kind() == reflect.Struct {
+		validateOnce.Do(func() { validate = validator.New() })
+		if err := validate.Struct(value); err != nil {
+			return fmt.Errorf("配置校验失败: %w", err)
+		}
+	}
+	if v, ok := value.(Validator); ok {
+		if err := v.Validate(); err != nil {
+			return fmt.Errorf("配置校验失败: %w", err)
+		}
+	}
+	return nil
+}
+
+// unmarshalProperties 将 properties 转换为嵌套结构后按 JSON 解码
+// 值按目标字段的类型转换，如 string 字段的 123456 仍为字符串；目标类型未知时按 YAML 标量规则推断
+func unmarshalProperties(data string, out interface{}) error {
+	root := make(map[string]interface{})
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	var pending string
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if pending != "" {
+			line = pending + line
+			pending = ""
+		}
+		if line == "" || line[0] == '#' || line[0] == '!' {
+			continue
+		}
+		if strings.HasSuffix(line, `\`) {
+			pending = strings.TrimSuffix(line, `\`)
+			continue
+		}
+
+		idx := strings.IndexAny(line, "=:")
+		if idx <= 0 {
+			return fmt.Errorf("properties 第%d行格式错误: %s", lineNo, line)
+		}
+		key := strings.TrimSpace(line[:idx])
+		raw := strings.TrimSpace(line[idx+1:])
+
+		parts := strings.Split(key, ".")
+		node := root
+		for _, part := range parts[:len(parts)-1] {
+			child, ok := node[part].(map[string]interface{})
+			if !ok {
+				child = make(map[string]interface{})
+				node[part] = child
+			}
+			node = child
+		}
+		node[parts[len(parts)-1]] = raw
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	buf, err := json.Marshal(coerceProperty(root, reflect.TypeOf(out)))
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(buf, out)
+}
+
+// coerceProperty 按目标类型转换 properties 的值，t 为 nil 表示目标类型未知
+func coerceProperty(v interface{}, t reflect.Type) interface{} {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch node := v.(type) {
+	case map[string]interface{}:
+		for key, child := range node {
+			node[key] = coerceProperty(child, propertyChildType(t, key))
+		}
+		return node
+	case string:
+		return coercePropertyScalar(node, t)
+	}
+	return v
+}
+
+// coercePropertyScalar 转换单个值，数字和布尔无法解析时保留字符串，由JSON解码报告类型错误
+func coercePropertyScalar(raw string, t reflect.Type) interface{} {
+	if t == nil || t.Kind() == reflect.Interface {
+		var scalar interface{}
+		if raw != "" && yaml.Unmarshal([]byte(raw), &scalar) == nil {
+			switch scalar.(type) {
+			case int, float64, bool:
+				return scalar
+			}
+		}
+		return raw
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		if b, err := strconv.ParseBool(raw); err == nil {
+			return b
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		if _, err := strconv.ParseFloat(raw, 64); err == nil && json.Valid([]byte(raw)) {
+			return json.Number(raw)
+		}
+	}
+	return raw
+}
+
+// propertyChildType 返回结构体字段（按 json 标签匹配，与 encoding/json 一致不区分大小写）或 map 元素的类型
+func propertyChildType(t reflect.Type, key string) reflect.Type {
+	if t == nil {
+		return nil
+	}
+	switch t.Kind() {
+	case reflect.Map:
+		return t.Elem()
+	case reflect.Struct:
+		if field, ok := jsonField(t, key, false); ok {
+			return field
+		}
+		if field, ok := jsonField(t, key, true); ok {
+			return field
+		}
+	}
+	return nil
+}
+
+// jsonField 按 json 名称查找字段，包括匿名嵌入结构体的字段
+func jsonField(t reflect.Type, key string, fold bool) (reflect.Type, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		ft := field.Type
+		for ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if field.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+			if found, ok := jsonField(ft, key, fold); ok {
+				return found, true
+			}
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		if name == key || (fold && strings.EqualFold(name, key)) {
+			return field.Type, true
+		}
+	}
+	return nil, false
+}
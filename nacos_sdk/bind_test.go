@@ -0,0 +1,100 @@
+package nacos_sdk
+
+import (
+	"errors"
+	"testing"
+)
+
+type bindTestConfig struct {
+	Name  string `json:"name" yaml:"name" toml:"name" validate:"required"`
+	Port  int    `json:"port" yaml:"port" toml:"port" validate:"min=1"`
+	Redis struct {
+		Addr  string `json:"addr" yaml:"addr" toml:"addr"`
+		Debug bool   `json:"debug" yaml:"debug" toml:"debug"`
+	} `json:"redis" yaml:"redis" toml:"redis"`
+}
+
+func (c *bindTestConfig) Validate() error {
+	if c.Port == 6666 {
+		return errors.New("port 6666 is reserved")
+	}
+	return nil
+}
+
+func TestParseConfigFormats(t *testing.T) {
+	cases := map[ConfigFormat]string{
+		FormatJSON:       `{"name":"room","port":8080,"redis":{"addr":"127.0.0.1:6379","debug":true}}`,
+		FormatYAML:       "name: room\nport: 8080\nredis:\n  addr: 127.0.0.1:6379\n  debug: true\n",
+		FormatTOML:       "name = \"room\"\nport = 8080\n[redis]\naddr = \"127.0.0.1:6379\"\ndebug = true\n",
+		FormatProperties: "# comment\nname=room\nport = 8080\nredis.addr=127.0.0.1:6379\nredis.debug=true\n",
+	}
+	for format, data := range cases {
+		cfg, err := ParseConfig[bindTestConfig](data, format)
+		if err != nil {
+			t.Fatalf("%s: %v", format, err)
+		}
+		if cfg.Name != "room" || cfg.Port != 8080 || cfg.Redis.Addr != "127.0.0.1:6379" || !cfg.Redis.Debug {
+			t.Fatalf("%s: unexpected config %+v", format, cfg)
+		}
+	}
+
+	if _, err := ParseConfig[bindTestConfig](`{"port":8080}`, FormatJSON); err == nil {
+		t.Fatalf("expected validate tag failure")
+	}
+	if _, err := ParseConfig[bindTestConfig](`{"name":"room","port":6666}`, FormatJSON); err == nil {
+		t.Fatalf("expected Validate() failure")
+	}
+	if FormatOf("room.yml") != FormatYAML || FormatOf("room") != FormatJSON {
+		t.Fatalf("unexpected format inference")
+	}
+}
+
+func TestParsePropertiesByFieldType(t *testing.T) {
+	type propertiesConfig struct {
+		Password string            `json:"password"`
+		Version  string            `json:"version"`
+		Enabled  string            `json:"enabled"`
+		Port     int               `json:"port"`
+		Ratio    float64           `json:"ratio"`
+		Labels   map[string]string `json:"labels"`
+		Extra    map[string]any    `json:"extra"`
+	}
+	data := "password=123456\nversion=1.10\nenabled=true\nport=8080\nratio=0.5\nlabels.zone=1\nextra.retries=3\n"
+	cfg, err := ParseConfig[propertiesConfig](data, FormatProperties)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if cfg.Password != "123456" || cfg.Version != "1.10" || cfg.Enabled != "true" || cfg.Labels["zone"] != "1" {
+		t.Fatalf("numeric-looking strings not kept: %+v", cfg)
+	}
+	if cfg.Port != 8080 || cfg.Ratio != 0.5 || cfg.Extra["retries"] != float64(3) {
+		t.Fatalf("unexpected typed values: %+v", cfg)
+	}
+	if _, err := ParseConfig[propertiesConfig]("port=abc\n", FormatProperties); err == nil {
+		t.Fatalf("expected error for non-numeric port")
+	}
+}
+
+func TestBindingUpdate(t *testing.T) {
+	b := newBinding[bindTestConfig]("room.json", "DEFAULT_GROUP", "")
+	if err := b.Update(`{"name":"room","port":8080}`); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+
+	var gotOld, gotNew *bindTestConfig
+	b.OnChange(func(old, new *bindTestConfig) { gotOld, gotNew = old, new })
+
+	if err := b.Update(`{"name":"","port":9090}`); err == nil {
+		t.Fatalf("expected invalid config to be rejected")
+	}
+	if b.Get().Port != 8080 || gotNew != nil {
+		t.Fatalf("invalid config should not replace current value")
+	}
+
+	if err := b.Update(`{"name":"room","port":9090}`); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if b.Get().Port != 9090 || gotOld.Port != 8080 || gotNew.Port != 9090 {
+		t.Fatalf("unexpected listener values old=%+v new=%+v", gotOld, gotNew)
+	}
+}
@@ -0,0 +1,218 @@
+package nacos_sdk
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/nacos-group/nacos-sdk-go/v2/clients"
+	"github.com/nacos-group/nacos-sdk-go/v2/clients/config_client"
+	"github.com/nacos-group/nacos-sdk-go/v2/clients/naming_client"
+	"github.com/nacos-group/nacos-sdk-go/v2/common/constant"
+	"github.com/nacos-group/nacos-sdk-go/v2/vo"
+)
+
+// Client Nacos客户端，持有自己的配置并按需创建配置客户端和服务发现客户端
+// 一个进程可以创建多个 Client 访问不同的命名空间，包级函数委托给默认客户端
+type Client struct {
+	config NacosConfig
+
+	initOnce      sync.Once
+	initErr       error
+	serverConfigs []constant.ServerConfig
+	clientConfig  constant.ClientConfig
+
+	configOnce   sync.Once
+	configClient config_client.IConfigClient
+	configErr    error
+	configReady  atomic.Bool  // 配置客户端已创建，用于不触发创建地读取状态
+	configFailed atomic.Value // 配置客户端创建失败的原因
+
+	namingOnce   sync.Once
+	namingClient naming_client.INamingClient
+	namingErr    error
+	namingReady  atomic.Bool
+	namingFailed atomic.Value
+
+	// 已注册的服务实例，用于心跳检查、重新注册和退出时注销
+	registeredServices map[string]*RegisteredServiceInfo
+	serviceCheckMutex  sync.Mutex
+	watchdog           *Watchdog
+
+	// 服务订阅，同一服务只向SDK订阅一次
+	watchMu sync.Mutex
+	watches map[string]*serviceWatch
+
+	// 订阅的服务和获取过的配置，用于调试接口
+	stateMu       sync.Mutex
+	subscriptions map[string]int
+	configStates  map[string]*ConfigState
+}
+
+// NewClient 创建Nacos客户端，底层客户端在首次使用时创建
+func NewClient(config NacosConfig) *Client {
+	return &Client{
+		config:             config,
+		registeredServices: make(map[string]*RegisteredServiceInfo),
+		watches:            make(map[string]*serviceWatch),
+		subscriptions:      make(map[string]int),
+		configStates:       make(map[string]*ConfigState),
+	}
+}
+
+var (
+	defaultClient   = NewClient(NacosConfig{})
+	defaultClientMu sync.RWMutex
+)
+
+// DefaultClient 返回包级函数使用的默认客户端
+func DefaultClient() *Client {
+	defaultClientMu.RLock()
+	defer defaultClientMu.RUnlock()
+	return defaultClient
+}
+
+// SetDefaultClient 替换默认客户端并返回之前的客户端
+func SetDefaultClient(c *Client) *Client {
+	defaultClientMu.Lock()
+	defer defaultClientMu.Unlock()
+	old := defaultClient
+	defaultClient = c
+	return old
+}
+
+// Config 返回客户端配置
+func (c *Client) Config() NacosConfig {
+	return c.config
+}
+
+// Group 返回当前服务所在的分组
+func (c *Client) Group() string {
+	return c.config.NacosGroup
+}
+
+// init 初始化Nacos客户端配置，只执行一次
+func (c *Client) init() error {
+	c.initOnce.Do(func() {
+		c.initErr = c.initNacosConfig()
+	})
+	return c.initErr
+}
+
+// GetConfigClient 获取配置客户端
+// 第一次调用时会初始化客户端，后续调用返回已初始化的实例
+// 返回配置客户端实例和可能的错误
+// 离线模式下返回错误
+func (c *Client) GetConfigClient() (config_client.IConfigClient, error) {
+	if c.IsOffline() {
+		return nil, fmt.Errorf("nacos离线模式，配置客户端不可用")
+	}
+	c.configOnce.Do(func() {
+		defer func() {
+			if c.configErr != nil {
+				c.configFailed.Store(c.configErr.Error())
+			}
+		}()
+		// 初始化Nacos配置
+		if err := c.init(); err != nil {
+			c.configErr = fmt.Errorf("初始化Nacos配置失败: %v", err)
+			return
+		}
+
+		// 创建配置客户端
+		client, err := clients.CreateConfigClient(map[string]interface{}{
+			"serverConfigs": c.serverConfigs,
+			"clientConfig":  c.clientConfig,
+		})
+		if err != nil {
+			c.configErr = fmt.Errorf("创建Nacos配置客户端失败: %v", err)
+			return
+		}
+		c.configClient = client
+		c.configReady.Store(true)
+		log.Print("Nacos配置客户端初始化成功")
+	})
+
+	return c.configClient, c.configErr
+}
+
+// GetNamingClient 获取服务发现客户端
+// 返回命名服务客户端实例和可能的错误
+func (c *Client) GetNamingClient() (naming_client.INamingClient, error) {
+	c.namingOnce.Do(func() {
+		defer func() {
+			if c.namingErr != nil {
+				c.namingFailed.Store(c.namingErr.Error())
+			}
+		}()
+		// 初始化Nacos配置
+		if err := c.init(); err != nil {
+			c.namingErr = fmt.Errorf("初始化Nacos配置失败: %v", err)
+			return
+		}
+
+		// 创建服务发现客户端
+		client, err := clients.CreateNamingClient(map[string]interface{}{
+			"serverConfigs": c.serverConfigs,
+			"clientConfig":  c.clientConfig,
+		})
+		if err != nil {
+			c.namingErr = fmt.Errorf("创建Nacos服务发现客户端失败: %v", err)
+			return
+		}
+
+		// 等待客户端连接就绪
+		for i := 0; i < 10; i++ {
+			// 尝试获取服务列表，检查连接状态
+			_, err := client.GetAllServicesInfo(vo.GetAllServiceInfoParam{
+				PageNo:   1,
+				PageSize: 10,
+			})
+			if err == nil {
+				// 连接成功
+				c.namingClient = client
+				c.namingReady.Store(true)
+				log.Printf("Nacos服务发现客户端初始化成功")
+				return
+			}
+			log.Printf("等待Nacos服务发现客户端连接就绪，重试次数: %d, 错误: %v", i+1, err)
+			time.Sleep(1 * time.Second)
+		}
+
+		if c.namingClient == nil {
+			c.namingErr = fmt.Errorf("初始化Nacos服务发现客户端超时")
+		}
+	})
+
+	return c.namingClient, c.namingErr
+}
+
+// Close 停止注册自愈并关闭已创建的配置客户端和服务发现客户端
+func (c *Client) Close() {
+	c.StopWatchdog()
+
+	// 防止关闭后再创建
+	c.configOnce.Do(func() { c.configErr = fmt.Errorf("nacos客户端已关闭") })
+	c.namingOnce.Do(func() { c.namingErr = fmt.Errorf("nacos客户端已关闭") })
+
+	c.configReady.Store(false)
+	c.namingReady.Store(false)
+	if c.configClient != nil {
+		c.configClient.CloseClient()
+	}
+	if c.namingClient != nil {
+		c.namingClient.CloseClient()
+	}
+}
+
+// GetConfigClient 获取默认客户端的配置客户端
+func GetConfigClient() (config_client.IConfigClient, error) {
+	return DefaultClient().GetConfigClient()
+}
+
+// GetNamingClient 获取默认客户端的服务发现客户端
+func GetNamingClient() (naming_client.INamingClient, error) {
+	return DefaultClient().GetNamingClient()
+}
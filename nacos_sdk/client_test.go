@@ -0,0 +1,57 @@
+package nacos_sdk
+
+import "testing"
+
+func TestClientServerConfigs(t *testing.T) {
+	c := NewClient(NacosConfig{
+		Scheme: "https",
+		Servers: []ServerAddress{
+			{Address: "10.0.0.1", Port: 8848},
+			{Address: "10.0.0.2", Port: 8848, Scheme: "http", ContextPath: "/mse"},
+		},
+	})
+	servers, err := c.buildServerConfigs()
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	if len(servers) != 2 || servers[0].Scheme != "https" || servers[1].Scheme != "http" || servers[1].ContextPath != "/mse" {
+		t.Fatalf("unexpected servers: %+v", servers)
+	}
+
+	legacy, _ := NewClient(NacosConfig{NacosAddress: "127.0.0.1", NacosPort: 8848}).buildServerConfigs()
+	if len(legacy) != 1 || legacy[0].IpAddr != "127.0.0.1" || legacy[0].Scheme != "http" {
+		t.Fatalf("unexpected legacy server: %+v", legacy)
+	}
+	if _, err := NewClient(NacosConfig{}).buildServerConfigs(); err == nil {
+		t.Fatalf("expected error without servers")
+	}
+}
+
+func TestInitNacosSDKReplacesDefault(t *testing.T) {
+	old := DefaultClient()
+	defer SetDefaultClient(old)
+
+	InitNacosSDK(NacosConfig{NacosGroup: "A"})
+	first := DefaultClient()
+	InitNacosSDK(NacosConfig{NacosGroup: "B"})
+	if DefaultClient() == first || DefaultClient().Group() != "B" {
+		t.Fatalf("expected InitNacosSDK to replace the default client")
+	}
+	if _, err := first.GetNamingClient(); err == nil {
+		t.Fatalf("replaced client should be closed")
+	}
+}
+
+func TestAdvertiseIP(t *testing.T) {
+	t.Setenv(advertiseIPEnv, "10.1.1.1")
+	if ip, err := NewClient(NacosConfig{}).AdvertiseIP(); err != nil || ip != "10.1.1.1" {
+		t.Fatalf("expected env override, got %s, %v", ip, err)
+	}
+	if ip, err := NewClient(NacosConfig{AdvertiseIP: "10.2.2.2"}).AdvertiseIP(); err != nil || ip != "10.2.2.2" {
+		t.Fatalf("expected config override, got %s, %v", ip, err)
+	}
+	// 指定的地址无效时不能回退到 127.0.0.1 注册
+	if ip, err := NewClient(NacosConfig{AdvertiseIP: "10.2.2"}).AdvertiseIP(); err == nil || ip != "" {
+		t.Fatalf("expected invalid override error, got %s, %v", ip, err)
+	}
+}
@@ -1,13 +1,14 @@
 package nacos_sdk
 
-var (
-	nacosAddress   string
-	nacosPort      uint64
-	nacosNameSpace string
-	projectName    string
-	nacosGroup     string
-	rpcPort        string
-)
+import "io/fs"
+
+// ServerAddress Nacos服务器地址
+type ServerAddress struct {
+	Address     string
+	Port        uint64
+	Scheme      string // 为空时使用 NacosConfig.Scheme
+	ContextPath string // 为空时使用 /nacos
+}
 
 type NacosConfig struct {
 	NacosAddress   string
@@ -16,13 +17,51 @@ type NacosConfig struct {
 	ProjectName    string
 	NacosGroup     string
 	RpcPort        string
+
+	// Servers 多个Nacos服务器地址，为空时使用 NacosAddress:NacosPort
+	Servers []ServerAddress
+	// Scheme 服务器协议，默认 http
+	Scheme string
+
+	// Username/Password 开启鉴权时的用户名密码
+	Username string
+	Password string
+	// AccessKey/SecretKey 阿里云MSE等使用的AK/SK鉴权
+	AccessKey string
+	SecretKey string
+
+	// TimeoutMs 请求超时，默认10000
+	TimeoutMs uint64
+	// BeatIntervalMs 心跳间隔，默认1000
+	BeatIntervalMs int64
+	// LogLevel Nacos SDK日志级别，默认 debug
+	LogLevel string
+	// LogDir/CacheDir Nacos SDK日志和缓存目录，日志默认在 工作目录/nacos_sdk/log
+	LogDir   string
+	CacheDir string
+
+	// AdvertiseIP 注册到Nacos的地址，为空时读取环境变量 NACOS_ADVERTISE_IP，仍为空时按下面的策略选择
+	AdvertiseIP string
+	// PreferredInterfaces 优先使用的网卡名，支持通配符，如 eth0、ens*
+	PreferredInterfaces []string
+	// PreferredCIDRs 优先使用的网段，如 10.0.0.0/8
+	PreferredCIDRs []string
+	// IPv6 是否允许注册IPv6地址
+	IPv6 bool
+
+	// SnapshotDir 配置快照目录，每次成功从Nacos获取配置后写入 <SnapshotDir>/<namespace>/<group>/<dataId>
+	// 为空时使用 工作目录/nacos_sdk/snapshot
+	SnapshotDir string
+	// DefaultConfigs 默认配置文件，通常为 embed.FS，按 <group>/<dataId> 或 <dataId> 查找
+	DefaultConfigs fs.FS
+	// Offline 离线模式，只从快照目录和默认配置读取，不连接Nacos；也可通过环境变量 NACOS_OFFLINE=true 开启
+	Offline bool
 }
 
+// InitNacosSDK 使用配置创建默认客户端，包级函数均委托给默认客户端
+// 重复调用会替换并关闭之前的默认客户端
 func InitNacosSDK(config NacosConfig) {
-	nacosAddress = config.NacosAddress
-	nacosPort = config.NacosPort
-	nacosNameSpace = config.NacosNameSpace
-	projectName = config.ProjectName
-	nacosGroup = config.NacosGroup
-	rpcPort = config.RpcPort
+	if old := SetDefaultClient(NewClient(config)); old != nil {
+		old.Close()
+	}
 }
@@ -1,58 +1,26 @@
 package nacos_sdk
 
 import (
-	"fmt"
 	"log"
-	"sync"
 
-	"github.com/nacos-group/nacos-sdk-go/v2/clients"
-	"github.com/nacos-group/nacos-sdk-go/v2/clients/config_client"
 	"github.com/nacos-group/nacos-sdk-go/v2/vo"
 )
 
-// 单例模式实现，确保整个应用只有一个nacos配置客户端实例
-var (
-	configClientInstance config_client.IConfigClient // 全局配置客户端实例
-	once                 sync.Once                   // 用于确保初始化只执行一次
-	initErr              error                       // 初始化过程中可能发生的错误
-)
-
-// GetConfigClient 获取单例配置客户端
-// 第一次调用时会初始化客户端，后续调用返回已初始化的实例
-// 返回配置客户端实例和可能的错误
-func GetConfigClient() (config_client.IConfigClient, error) {
-	once.Do(func() {
-		// 初始化Nacos配置
-		if err := initNacosConfig(); err != nil {
-			initErr = fmt.Errorf("初始化Nacos配置失败: %v", err)
-			return
-		}
-
-		// 创建配置客户端
-		client, err := clients.CreateConfigClient(map[string]interface{}{
-			"serverConfigs": serverConfigs,
-			"clientConfig":  clientConfig,
-		})
-		if err != nil {
-			initErr = fmt.Errorf("创建Nacos配置客户端失败: %v", err)
-			return
-		}
-		configClientInstance = client
-		log.Print("Nacos配置客户端初始化成功")
-	})
-
-	return configClientInstance, initErr
-}
-
 // GetConfigValue 获取配置值
 // 根据dataId和group查询配置内容
+// 依次尝试 Nacos、本地快照、默认配置，成功从Nacos获取非空内容后更新本地快照；离线模式下只读本地
+// SDK 不使用自己的本地缓存兜底（DisableUseSnapShot），Nacos不可用时由本地快照兜底，日志中的来源与实际一致
 // dataId: 配置ID
 // group: 配置分组
 // 返回配置内容和可能的错误
-func GetConfigValue(dataId, group string) (string, error) {
-	client, err := GetConfigClient()
+func (c *Client) GetConfigValue(dataId, group string) (string, error) {
+	if c.IsOffline() {
+		return c.getLocalConfigValue(dataId, group, nil)
+	}
+
+	client, err := c.GetConfigClient()
 	if err != nil {
-		return "", err
+		return c.getLocalConfigValue(dataId, group, err)
 	}
 
 	value, err := client.GetConfig(vo.ConfigParam{
@@ -62,9 +30,18 @@ func GetConfigValue(dataId, group string) (string, error) {
 	if err != nil {
 
 		log.Printf("获取配置失败 [%s:%s]: %v", group, dataId, err)
-		return "", err
+		return c.getLocalConfigValue(dataId, group, err)
 	}
 
+	if c.isFailoverConfig(dataId, group) {
+		log.Printf("获取配置 [%s:%s] 来源: %s", group, dataId, ConfigSourceFailover)
+		c.trackConfig(dataId, group, ConfigSourceFailover, value, false)
+		return value, nil
+	}
+
+	log.Printf("获取配置 [%s:%s] 来源: %s", group, dataId, ConfigSourceNacos)
+	c.writeSnapshot(dataId, group, value)
+	c.trackConfig(dataId, group, ConfigSourceNacos, value, false)
 	return value, nil
 }
 
@@ -73,9 +50,15 @@ func GetConfigValue(dataId, group string) (string, error) {
 // dataId: 配置ID
 // group: 配置分组
 // onChange: 配置变更回调函数，参数为新的配置内容
+// 配置变更时同步更新本地快照，离线模式下不监听
 // 返回可能的错误
-func ListenConfigChange(dataId, group string, onChange func(data string)) error {
-	client, err := GetConfigClient()
+func (c *Client) ListenConfigChange(dataId, group string, onChange func(data string)) error {
+	if c.IsOffline() {
+		log.Printf("离线模式，不监听配置变更 [%s:%s]", group, dataId)
+		return nil
+	}
+
+	client, err := c.GetConfigClient()
 	if err != nil {
 		return err
 	}
@@ -85,6 +68,8 @@ func ListenConfigChange(dataId, group string, onChange func(data string)) error
 		Group:  group,
 		OnChange: func(namespace, group, dataId, data string) {
 			log.Printf("配置变更 [%s:%s]", group, dataId)
+			c.writeSnapshot(dataId, group, data)
+			c.trackConfig(dataId, group, ConfigSourceNacos, data, true)
 			onChange(data)
 		},
 	})
@@ -93,6 +78,17 @@ func ListenConfigChange(dataId, group string, onChange func(data string)) error
 		return err
 	}
 
+	c.trackListening(dataId, group)
 	log.Printf("开始监听配置 [%s:%s]", group, dataId)
 	return nil
 }
+
+// GetConfigValue 从默认客户端获取配置值，见 Client.GetConfigValue
+func GetConfigValue(dataId, group string) (string, error) {
+	return DefaultClient().GetConfigValue(dataId, group)
+}
+
+// ListenConfigChange 通过默认客户端监听配置变更，见 Client.ListenConfigChange
+func ListenConfigChange(dataId, group string, onChange func(data string)) error {
+	return DefaultClient().ListenConfigChange(dataId, group, onChange)
+}
@@ -0,0 +1,141 @@
+package nacos_sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/Dev-Umb/go-pkg/logger"
+
+	"github.com/nacos-group/nacos-sdk-go/v2/clients/cache"
+	"github.com/nacos-group/nacos-sdk-go/v2/common/constant"
+	nacosutil "github.com/nacos-group/nacos-sdk-go/v2/util"
+)
+
+// 配置来源，用于日志
+const (
+	ConfigSourceNacos    = "nacos"
+	ConfigSourceFailover = "failover" // Nacos SDK 的容灾文件，见 isFailoverConfig
+	ConfigSourceSnapshot = "snapshot"
+	ConfigSourceDefault  = "default"
+)
+
+// offlineEnv 开启离线模式的环境变量
+const offlineEnv = "NACOS_OFFLINE"
+
+// IsOffline 是否处于离线模式
+func (c *Client) IsOffline() bool {
+	if c.config.Offline {
+		return true
+	}
+	v, _ := strconv.ParseBool(os.Getenv(offlineEnv))
+	return v
+}
+
+// snapshotPath 返回配置快照文件路径 <SnapshotDir>/<namespace>/<group>/<dataId>
+// 命名空间为空时使用 public，namespace、dataId 和 group 不能包含路径分隔符或为 .、..
+func (c *Client) snapshotPath(dataId, group string) (string, error) {
+	namespace := c.config.NacosNameSpace
+	if namespace == "" {
+		namespace = "public"
+	}
+	if !validSnapshotName(namespace) {
+		return "", fmt.Errorf("命名空间不能用作快照目录名 [%s]", namespace)
+	}
+	if !validSnapshotName(dataId) || (group != "" && !validSnapshotName(group)) {
+		return "", fmt.Errorf("dataId或group不能用作快照文件名 [%s:%s]", group, dataId)
+	}
+	dir := c.config.SnapshotDir
+	if dir == "" {
+		workDir, err := os.Getwd()
+		if err != nil {
+			return "", err
+		}
+		dir = filepath.Join(workDir, "nacos_sdk", "snapshot")
+	}
+	return filepath.Join(dir, namespace, group, dataId), nil
+}
+
+// validSnapshotName 判断名称能否作为快照路径的一级，避免写到快照目录之外
+func validSnapshotName(name string) bool {
+	return name != "" && name != "." && name != ".." && !strings.ContainsAny(name, `/\`)
+}
+
+// writeSnapshot 将从Nacos获取的配置写入本地快照，先写临时文件再重命名，避免读到半个文件
+// 内容为空时不写入，避免覆盖之前的有效快照
+func (c *Client) writeSnapshot(dataId, group, content string) {
+	if content == "" {
+		return
+	}
+	file, err := c.snapshotPath(dataId, group)
+	if err == nil {
+		err = os.MkdirAll(filepath.Dir(file), 0755)
+	}
+	if err == nil {
+		tmp := file + ".tmp"
+		if err = os.WriteFile(tmp, []byte(content), 0644); err == nil {
+			err = os.Rename(tmp, file)
+		}
+	}
+	if err != nil {
+		logger.Warnf(context.Background(), "写入配置快照失败 [%s:%s]: %v", group, dataId, err)
+	}
+}
+
+// isFailoverConfig 判断 GetConfig 返回的内容是否来自SDK的容灾文件
+// SDK 在容灾文件存在时直接返回其内容且不报错，这类内容不写入快照
+func (c *Client) isFailoverConfig(dataId, group string) bool {
+	if group == "" {
+		group = constant.DEFAULT_GROUP
+	}
+	key := nacosutil.GetConfigCacheKey(dataId, group, c.clientConfig.NamespaceId)
+	dir := filepath.Join(c.clientConfig.CacheDir, "config")
+	_, err := os.Stat(cache.GetConfigFailOverContentFileName(key, dir))
+	return err == nil
+}
+
+// readLocalConfig 依次从本地快照和默认配置读取，返回内容和来源
+func (c *Client) readLocalConfig(dataId, group string) (string, string, error) {
+	if file, err := c.snapshotPath(dataId, group); err == nil {
+		if data, err := os.ReadFile(file); err == nil {
+			return string(data), ConfigSourceSnapshot, nil
+		}
+	}
+	if c.config.DefaultConfigs != nil {
+		for _, name := range []string{path.Join(group, dataId), dataId} {
+			if data, err := fs.ReadFile(c.config.DefaultConfigs, name); err == nil {
+				return string(data), ConfigSourceDefault, nil
+			}
+		}
+	}
+	return "", "", fmt.Errorf("本地没有可用的配置 [%s:%s]", group, dataId)
+}
+
+// getLocalConfigValue 从本地读取配置，cause 为Nacos获取失败的原因，离线模式下为空
+func (c *Client) getLocalConfigValue(dataId, group string, cause error) (string, error) {
+	value, source, err := c.readLocalConfig(dataId, group)
+	if err != nil {
+		if cause != nil {
+			return "", errors.Join(cause, err)
+		}
+		return "", err
+	}
+	c.trackConfig(dataId, group, source, value, false)
+	if cause != nil {
+		logger.Warnf(context.Background(), "从Nacos获取配置失败，使用本地配置 [%s:%s] 来源: %s, 原因: %v", group, dataId, source, cause)
+	} else {
+		logger.Infof(context.Background(), "离线模式读取配置 [%s:%s] 来源: %s", group, dataId, source)
+	}
+	return value, nil
+}
+
+// IsOffline 默认客户端是否处于离线模式
+func IsOffline() bool {
+	return DefaultClient().IsOffline()
+}
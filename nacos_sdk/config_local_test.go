@@ -0,0 +1,91 @@
+package nacos_sdk
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+)
+
+func TestOfflineConfigFallback(t *testing.T) {
+	dir := t.TempDir()
+	c := NewClient(NacosConfig{
+		SnapshotDir: dir,
+		DefaultConfigs: fstest.MapFS{
+			"room.json":           {Data: []byte(`{"name":"default","port":8080}`)},
+			"GROUP_A/common.json": {Data: []byte(`{"name":"common","port":9000}`)},
+		},
+		Offline: true,
+	})
+
+	value, err := c.GetConfigValue("room.json", "GROUP_A")
+	if err != nil || value != `{"name":"default","port":8080}` {
+		t.Fatalf("expected embedded default, got %q, %v", value, err)
+	}
+	if value, _ := c.GetConfigValue("common.json", "GROUP_A"); value != `{"name":"common","port":9000}` {
+		t.Fatalf("expected grouped default, got %q", value)
+	}
+
+	// 快照优先于默认配置
+	c.writeSnapshot("room.json", "GROUP_A", `{"name":"snapshot","port":8081}`)
+	if _, err := os.Stat(filepath.Join(dir, "public", "GROUP_A", "room.json")); err != nil {
+		t.Fatalf("snapshot not written: %v", err)
+	}
+	cfg, err := BindWith[bindTestConfig](c, "room.json", "GROUP_A", "")
+	if err != nil {
+		t.Fatalf("bind offline: %v", err)
+	}
+	if cfg.Get().Name != "snapshot" {
+		t.Fatalf("expected snapshot config, got %+v", cfg.Get())
+	}
+
+	if _, err := c.GetConfigValue("missing.json", "GROUP_A"); err == nil {
+		t.Fatalf("expected error for missing config")
+	}
+	if _, err := c.GetConfigClient(); err == nil {
+		t.Fatalf("config client should be unavailable offline")
+	}
+}
+
+func TestSnapshotGuards(t *testing.T) {
+	dir := t.TempDir()
+	c := NewClient(NacosConfig{SnapshotDir: dir, Offline: true})
+
+	// 空内容不能覆盖已有的快照
+	c.writeSnapshot("room.json", "G", `{"name":"room"}`)
+	c.writeSnapshot("room.json", "G", "")
+	if value, err := c.GetConfigValue("room.json", "G"); err != nil || value != `{"name":"room"}` {
+		t.Fatalf("expected previous snapshot, got %q, %v", value, err)
+	}
+
+	// 不能写到快照目录之外
+	for _, name := range [][2]string{{"../escape.json", "G"}, {"escape.json", ".."}, {`a\b.json`, "G"}, {"x.json", "G/../.."}} {
+		if _, err := c.snapshotPath(name[0], name[1]); err == nil {
+			t.Fatalf("expected %q/%q to be rejected", name[1], name[0])
+		}
+		c.writeSnapshot(name[0], name[1], "x")
+	}
+	if _, err := os.Stat(filepath.Join(filepath.Dir(dir), "escape.json")); err == nil {
+		t.Fatalf("snapshot written outside snapshot dir")
+	}
+	bad := NewClient(NacosConfig{SnapshotDir: dir, NacosNameSpace: "..", Offline: true})
+	if _, err := bad.snapshotPath("room.json", "G"); err == nil {
+		t.Fatalf("expected namespace %q to be rejected", "..")
+	}
+}
+
+func TestSnapshotNamespaces(t *testing.T) {
+	dir := t.TempDir()
+	dev := NewClient(NacosConfig{SnapshotDir: dir, NacosNameSpace: "dev", Offline: true})
+	prod := NewClient(NacosConfig{SnapshotDir: dir, NacosNameSpace: "prod", Offline: true})
+
+	// 共用快照目录的不同命名空间互不覆盖
+	dev.writeSnapshot("room.json", "G", `{"name":"dev"}`)
+	prod.writeSnapshot("room.json", "G", `{"name":"prod"}`)
+	if value, err := dev.GetConfigValue("room.json", "G"); err != nil || value != `{"name":"dev"}` {
+		t.Fatalf("expected dev snapshot, got %q, %v", value, err)
+	}
+	if value, err := prod.GetConfigValue("room.json", "G"); err != nil || value != `{"name":"prod"}` {
+		t.Fatalf("expected prod snapshot, got %q, %v", value, err)
+	}
+}
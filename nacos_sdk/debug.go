@@ -0,0 +1,260 @@
+package nacos_sdk
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nacos-group/nacos-sdk-go/v2/vo"
+)
+
+// ConfigState 获取或监听过的配置
+type ConfigState struct {
+	DataId    string    `json:"dataId"`
+	Group     string    `json:"group"`
+	Source    string    `json:"source"`    // 最近一次内容的来源：nacos、snapshot、default
+	MD5       string    `json:"md5"`       // 与Nacos控制台一致的内容MD5
+	Listening bool      `json:"listening"` // 是否在监听变更
+	UpdatedAt time.Time `json:"updatedAt"` // 最近一次获取或收到变更的时间
+	Changes   int       `json:"changes"`   // 收到变更的次数
+}
+
+// Connectivity Nacos连接状态
+type Connectivity struct {
+	Offline      bool   `json:"offline"`
+	ConfigClient bool   `json:"configClient"`          // 配置客户端已创建
+	ConfigError  string `json:"configError,omitempty"` // 配置客户端创建失败的原因
+	NamingClient bool   `json:"namingClient"`          // 服务发现客户端已创建
+	NamingError  string `json:"namingError,omitempty"` // 服务发现客户端创建失败的原因，如启动时连接超时
+	NamingServer bool   `json:"namingServer"`          // 服务发现客户端与服务端连接正常
+}
+
+// InstanceState 订阅服务的实例
+type InstanceState struct {
+	IP       string            `json:"ip"`
+	Port     uint64            `json:"port"`
+	Cluster  string            `json:"cluster,omitempty"`
+	Weight   float64           `json:"weight"`
+	Healthy  bool              `json:"healthy"`
+	Enabled  bool              `json:"enabled"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// SubscribedService 订阅的服务及其当前实例
+type SubscribedService struct {
+	Service   string          `json:"service"`
+	Group     string          `json:"group"`
+	Instances []InstanceState `json:"instances"`
+	Error     string          `json:"error,omitempty"`
+}
+
+// RegisteredInstance 当前进程注册的实例
+type RegisteredInstance struct {
+	Service       string            `json:"service"`
+	Group         string            `json:"group"`
+	IP            string            `json:"ip"`
+	Port          uint64            `json:"port"`
+	Cluster       string            `json:"cluster,omitempty"`
+	Weight        float64           `json:"weight"`
+	Ephemeral     bool              `json:"ephemeral"`
+	Metadata      map[string]string `json:"metadata,omitempty"`
+	LastHeartbeat time.Time         `json:"lastHeartbeat"`
+}
+
+// DiscoveryState 服务发现和配置的运行状态
+type DiscoveryState struct {
+	Connectivity Connectivity         `json:"connectivity"`
+	Registered   []RegisteredInstance `json:"registered"`
+	Subscribed   []SubscribedService  `json:"subscribed"`
+	Configs      []ConfigState        `json:"configs"`
+	Watchdog     *WatchdogStats       `json:"watchdog,omitempty"`
+}
+
+// trackSubscription 记录服务订阅，delta 为订阅回调数的变化
+func (c *Client) trackSubscription(serviceName, group string, delta int) {
+	key := watchKey(serviceName, group)
+	c.stateMu.Lock()
+	defer c.stateMu.Unlock()
+	c.subscriptions[key] += delta
+	if c.subscriptions[key] <= 0 {
+		delete(c.subscriptions, key)
+	}
+}
+
+// trackConfig 记录配置内容，changed 表示来自变更推送
+func (c *Client) trackConfig(dataId, group, source, content string, changed bool) {
+	sum := md5.Sum([]byte(content))
+	c.stateMu.Lock()
+	defer c.stateMu.Unlock()
+	state := c.configState(dataId, group)
+	state.Source = source
+	state.MD5 = hex.EncodeToString(sum[:])
+	state.UpdatedAt = time.Now()
+	if changed {
+		state.Changes++
+	}
+}
+
+// trackListening 记录开始监听的配置
+func (c *Client) trackListening(dataId, group string) {
+	c.stateMu.Lock()
+	defer c.stateMu.Unlock()
+	c.configState(dataId, group).Listening = true
+}
+
+// configState 返回配置状态，不存在时创建，调用方需持有 stateMu
+func (c *Client) configState(dataId, group string) *ConfigState {
+	key := group + "/" + dataId
+	state, ok := c.configStates[key]
+	if !ok {
+		state = &ConfigState{DataId: dataId, Group: group}
+		c.configStates[key] = state
+	}
+	return state
+}
+
+// Connectivity 返回连接状态，不会触发客户端创建
+func (c *Client) Connectivity() Connectivity {
+	conn := Connectivity{
+		Offline:      c.IsOffline(),
+		ConfigClient: c.configReady.Load(),
+		NamingClient: c.namingReady.Load(),
+	}
+	conn.ConfigError, _ = c.configFailed.Load().(string)
+	conn.NamingError, _ = c.namingFailed.Load().(string)
+	if conn.NamingClient {
+		conn.NamingServer = c.namingClient.ServerHealthy()
+	}
+	return conn
+}
+
+// Ready 是否可以对外服务：离线模式；或客户端没有创建失败，且没有使用服务发现或服务发现连接正常
+func (conn Connectivity) Ready() bool {
+	if conn.Offline {
+		return true
+	}
+	if conn.ConfigError != "" || conn.NamingError != "" {
+		return false
+	}
+	return !conn.NamingClient || conn.NamingServer
+}
+
+// DiscoveryState 返回当前进程的服务注册、订阅和配置状态
+// 订阅服务的实例从SDK的本地缓存读取
+func (c *Client) DiscoveryState() DiscoveryState {
+	state := DiscoveryState{
+		Connectivity: c.Connectivity(),
+		Registered:   []RegisteredInstance{},
+		Subscribed:   []SubscribedService{},
+		Configs:      []ConfigState{},
+	}
+
+	c.serviceCheckMutex.Lock()
+	for _, info := range c.registeredServices {
+		state.Registered = append(state.Registered, RegisteredInstance{
+			Service:       info.ServiceName,
+			Group:         info.Group,
+			IP:            info.IP,
+			Port:          info.Port,
+			Cluster:       info.Cluster,
+			Weight:        info.Weight,
+			Ephemeral:     info.Ephemeral,
+			Metadata:      info.Metadata,
+			LastHeartbeat: info.LastHeartbeat,
+		})
+	}
+	watchdog := c.watchdog
+	c.serviceCheckMutex.Unlock()
+	if watchdog != nil {
+		stats := watchdog.Stats()
+		state.Watchdog = &stats
+	}
+	sort.Slice(state.Registered, func(i, j int) bool {
+		a, b := state.Registered[i], state.Registered[j]
+		if a.Service != b.Service {
+			return a.Service < b.Service
+		}
+		return a.Port < b.Port
+	})
+
+	c.stateMu.Lock()
+	subscriptions := make([]string, 0, len(c.subscriptions))
+	for key := range c.subscriptions {
+		subscriptions = append(subscriptions, key)
+	}
+	for _, config := range c.configStates {
+		state.Configs = append(state.Configs, *config)
+	}
+	c.stateMu.Unlock()
+	sort.Strings(subscriptions)
+	sort.Slice(state.Configs, func(i, j int) bool {
+		a, b := state.Configs[i], state.Configs[j]
+		return a.Group+"/"+a.DataId < b.Group+"/"+b.DataId
+	})
+
+	for _, key := range subscriptions {
+		group, serviceName, _ := strings.Cut(key, "/")
+		subscribed := SubscribedService{Service: serviceName, Group: group, Instances: []InstanceState{}}
+		if state.Connectivity.NamingClient {
+			// SelectInstances 的 HealthyOnly 为 false 时只返回不健康实例，这里需要全部实例
+			service, err := c.namingClient.GetService(vo.GetServiceParam{
+				ServiceName: serviceName,
+				GroupName:   group,
+			})
+			if err != nil {
+				subscribed.Error = err.Error()
+			}
+			for _, instance := range service.Hosts {
+				subscribed.Instances = append(subscribed.Instances, InstanceState{
+					IP:       instance.Ip,
+					Port:     instance.Port,
+					Cluster:  instance.ClusterName,
+					Weight:   instance.Weight,
+					Healthy:  instance.Healthy,
+					Enabled:  instance.Enable,
+					Metadata: instance.Metadata,
+				})
+			}
+		}
+		state.Subscribed = append(state.Subscribed, subscribed)
+	}
+	return state
+}
+
+// DebugHandler 输出服务发现和配置状态的 gin 处理函数，用于排查问题
+// 直接输出状态，不使用统一响应结构
+func (c *Client) DebugHandler() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.JSON(http.StatusOK, c.DiscoveryState())
+	}
+}
+
+// HealthHandler 就绪探针的 gin 处理函数，未就绪时返回503
+func (c *Client) HealthHandler() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		conn := c.Connectivity()
+		if conn.Ready() {
+			ctx.JSON(http.StatusOK, gin.H{"status": "UP", "connectivity": conn})
+			return
+		}
+		ctx.JSON(http.StatusServiceUnavailable, gin.H{"status": "DOWN", "connectivity": conn})
+	}
+}
+
+// DebugHandler 输出默认客户端状态的 gin 处理函数，默认客户端在请求时获取
+func DebugHandler() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		DefaultClient().DebugHandler()(ctx)
+	}
+}
+
+// HealthHandler 默认客户端就绪探针的 gin 处理函数
+func HealthHandler() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		DefaultClient().HealthHandler()(ctx)
+	}
+}
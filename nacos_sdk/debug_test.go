@@ -0,0 +1,105 @@
+package nacos_sdk
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nacos-group/nacos-sdk-go/v2/model"
+	"github.com/nacos-group/nacos-sdk-go/v2/vo"
+)
+
+type debugNamingClient struct {
+	registerNamingClient
+	healthy bool
+}
+
+func (f *debugNamingClient) ServerHealthy() bool {
+	return f.healthy
+}
+
+func (f *debugNamingClient) GetService(param vo.GetServiceParam) (model.Service, error) {
+	return model.Service{Name: param.ServiceName, Hosts: []model.Instance{{Ip: "10.0.0.9", Port: 9000, Weight: 10, Healthy: true, Enable: true}}}, nil
+}
+
+func TestDebugHandlers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	c := NewClient(NacosConfig{SnapshotDir: t.TempDir(), Offline: false})
+	fake := &debugNamingClient{}
+	c.namingOnce.Do(func() {
+		c.namingClient = fake
+		c.namingReady.Store(true)
+	})
+
+	if _, err := c.RegisterServiceInstance("room", "10.0.0.1", 9000, "G", nil); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	if err := c.SubscribeService("user", "G", func([]model.Instance, error) {}); err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+	c.trackConfig("room.json", "G", ConfigSourceNacos, "hello", false)
+	c.trackListening("room.json", "G")
+	c.trackConfig("room.json", "G", ConfigSourceNacos, "hello", true)
+
+	r := gin.New()
+	r.GET("/debug", c.DebugHandler())
+	r.GET("/health", c.HealthHandler())
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/debug", nil))
+	var state DiscoveryState
+	if err := json.Unmarshal(w.Body.Bytes(), &state); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(state.Registered) != 1 || state.Registered[0].Service != "room" {
+		t.Fatalf("unexpected registered: %+v", state.Registered)
+	}
+	if len(state.Subscribed) != 1 || state.Subscribed[0].Service != "user" || len(state.Subscribed[0].Instances) != 1 {
+		t.Fatalf("unexpected subscribed: %+v", state.Subscribed)
+	}
+	cfg := state.Configs[0]
+	if !cfg.Listening || cfg.Changes != 1 || cfg.MD5 != "5d41402abc4b2a76b9719d911017c592" {
+		t.Fatalf("unexpected config state: %+v", cfg)
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/health", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 when naming server is unreachable, got %d", w.Code)
+	}
+	fake.healthy = true
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/health", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+}
+
+func TestHealthHandlerClientFailure(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	c := NewClient(NacosConfig{CacheDir: t.TempDir(), LogDir: t.TempDir()})
+	r := gin.New()
+	r.GET("/health", c.HealthHandler())
+
+	// 没有使用服务发现时就绪
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/health", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200 before naming client is used, got %d", w.Code)
+	}
+
+	// 服务发现客户端创建失败时不能报告就绪
+	if _, err := c.GetNamingClient(); err == nil {
+		t.Fatalf("expected naming client failure without server address")
+	}
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/health", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 after naming client failure, got %d", w.Code)
+	}
+	if conn := c.Connectivity(); conn.NamingError == "" || conn.NamingClient {
+		t.Fatalf("unexpected connectivity: %+v", conn)
+	}
+}
@@ -0,0 +1,243 @@
+package nacos_sdk
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/Dev-Umb/go-pkg/logger"
+
+	"github.com/nacos-group/nacos-sdk-go/v2/model"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// ConnManager gRPC连接管理器
+// 按 分组/服务/实例地址 缓存 *grpc.ClientConn 并复用，订阅服务变更，实例下线后关闭对应连接
+type ConnManager struct {
+	mu          sync.Mutex
+	conns       map[string]*pooledConn
+	watchers    map[string]*Subscription
+	dialOptions []grpc.DialOption
+	closed      bool
+
+	// 便于测试替换
+	healthy   func(serviceName, group string) (*model.Instance, error)
+	subscribe func(serviceName, group string, callback func(instances []model.Instance, err error)) (*Subscription, error)
+}
+
+// pooledConn 缓存的连接
+type pooledConn struct {
+	conn        *grpc.ClientConn
+	serviceName string
+	group       string
+	addr        string
+}
+
+// NewConnManager 创建gRPC连接管理器，opts 追加在默认拨号选项和 AddGRPCDialOptions 之后
+func NewConnManager(opts ...grpc.DialOption) *ConnManager {
+	return &ConnManager{
+		conns:       make(map[string]*pooledConn),
+		watchers:    make(map[string]*Subscription),
+		dialOptions: opts,
+		healthy:     GetHealthyInstance,
+		subscribe:   Subscribe,
+	}
+}
+
+// NewConnManager 创建使用该客户端发现实例的gRPC连接管理器
+func (c *Client) NewConnManager(opts ...grpc.DialOption) *ConnManager {
+	m := NewConnManager(opts...)
+	m.healthy = c.GetHealthyInstance
+	m.subscribe = c.Subscribe
+	return m
+}
+
+// defaultConnManager GetGRPCClient 使用的连接管理器
+var (
+	defaultConnManager   = NewConnManager()
+	defaultConnManagerMu sync.RWMutex
+)
+
+// getDefaultConnManager 返回 GetGRPCClient 使用的连接管理器
+func getDefaultConnManager() *ConnManager {
+	defaultConnManagerMu.RLock()
+	defer defaultConnManagerMu.RUnlock()
+	return defaultConnManager
+}
+
+// CloseGRPCConnections 关闭 GetGRPCClient 创建的所有连接，用于服务关闭
+// 关闭后替换为新的连接管理器，之后（如重新 InitNacosSDK 后）GetGRPCClient 仍可使用
+func CloseGRPCConnections() error {
+	defaultConnManagerMu.Lock()
+	old := defaultConnManager
+	defaultConnManager = NewConnManager()
+	defaultConnManagerMu.Unlock()
+	return old.Close()
+}
+
+func connKey(serviceName, group, addr string) string {
+	return group + "/" + serviceName + "/" + addr
+}
+
+func watchKey(serviceName, group string) string {
+	return group + "/" + serviceName
+}
+
+// splitGroupedServiceName 拆分Nacos返回的 "分组@@服务名" 格式的服务名
+func splitGroupedServiceName(name string) (serviceName, group string) {
+	if i := strings.Index(name, "@@"); i >= 0 {
+		return name[i+2:], name[:i]
+	}
+	return name, ""
+}
+
+// Get 选择一个健康实例并返回其连接，已有可用连接时直接复用
+func (m *ConnManager) Get(serviceName, group string) (*grpc.ClientConn, error) {
+	instance, err := m.healthy(serviceName, group)
+	if err != nil {
+		return nil, fmt.Errorf("获取服务实例失败: %v", err)
+	}
+	return m.GetForInstance(*instance, serviceName, group)
+}
+
+// GetForInstance 返回指定实例的连接，group 为空时从实例的服务名中解析
+func (m *ConnManager) GetForInstance(instance model.Instance, serviceName, group string) (*grpc.ClientConn, error) {
+	if group == "" {
+		_, group = splitGroupedServiceName(instance.ServiceName)
+	}
+	addr := fmt.Sprintf("%s:%d", instance.Ip, instance.Port)
+	key := connKey(serviceName, group, addr)
+
+	m.mu.Lock()
+	if m.closed {
+		m.mu.Unlock()
+		return nil, fmt.Errorf("gRPC连接管理器已关闭")
+	}
+	if pc, ok := m.conns[key]; ok && pc.conn.GetState() != connectivity.Shutdown {
+		m.mu.Unlock()
+		return pc.conn, nil
+	}
+
+	opts := append([]grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}, globalDialOptions()...)
+	opts = append(opts, m.dialOptions...)
+	conn, err := grpc.Dial(addr, opts...)
+	if err != nil {
+		m.mu.Unlock()
+		logger.Errorf(context.Background(), "连接服务实例失败: %s, 地址: %s, 错误: %v", serviceName, addr, err)
+		return nil, fmt.Errorf("连接服务实例失败: %s, 错误: %v", serviceName, err)
+	}
+	m.conns[key] = &pooledConn{conn: conn, serviceName: serviceName, group: group, addr: addr}
+	needWatch := group != ""
+	if _, ok := m.watchers[watchKey(serviceName, group)]; ok {
+		needWatch = false
+	}
+	m.mu.Unlock()
+
+	if needWatch {
+		m.watch(serviceName, group)
+	}
+	return conn, nil
+}
+
+// watch 订阅服务变更，实例消失时关闭连接
+func (m *ConnManager) watch(serviceName, group string) {
+	callback := func(instances []model.Instance, err error) {
+		if err != nil {
+			logger.Warnf(context.Background(), "服务订阅回调错误 [%s:%s]: %v", group, serviceName, err)
+			return
+		}
+		m.onInstancesChanged(serviceName, group, instances)
+	}
+
+	key := watchKey(serviceName, group)
+	m.mu.Lock()
+	if _, ok := m.watchers[key]; ok || m.closed {
+		m.mu.Unlock()
+		return
+	}
+	// 先占位，避免并发重复订阅
+	m.watchers[key] = nil
+	m.mu.Unlock()
+
+	sub, err := m.subscribe(serviceName, group, callback)
+	m.mu.Lock()
+	if err != nil {
+		delete(m.watchers, key)
+		m.mu.Unlock()
+		logger.Warnf(context.Background(), "订阅服务失败，实例下线后连接不会自动关闭 [%s:%s]: %v", group, serviceName, err)
+		return
+	}
+	if m.closed {
+		// 订阅期间管理器已关闭
+		m.mu.Unlock()
+		_ = sub.Unsubscribe()
+		return
+	}
+	m.watchers[key] = sub
+	m.mu.Unlock()
+}
+
+// onInstancesChanged 关闭已不在实例列表中的连接
+func (m *ConnManager) onInstancesChanged(serviceName, group string, instances []model.Instance) {
+	alive := make(map[string]struct{}, len(instances))
+	for _, instance := range instances {
+		alive[fmt.Sprintf("%s:%d", instance.Ip, instance.Port)] = struct{}{}
+	}
+
+	var stale []*pooledConn
+	m.mu.Lock()
+	for key, pc := range m.conns {
+		if pc.serviceName != serviceName || pc.group != group {
+			continue
+		}
+		if _, ok := alive[pc.addr]; !ok {
+			stale = append(stale, pc)
+			delete(m.conns, key)
+		}
+	}
+	m.mu.Unlock()
+
+	for _, pc := range stale {
+		logger.Infof(context.Background(), "服务实例已下线，关闭gRPC连接 [%s:%s] %s", group, serviceName, pc.addr)
+		if err := pc.conn.Close(); err != nil {
+			logger.Warnf(context.Background(), "关闭gRPC连接失败 [%s:%s] %s: %v", group, serviceName, pc.addr, err)
+		}
+	}
+}
+
+// Close 取消服务订阅并关闭所有连接
+func (m *ConnManager) Close() error {
+	m.mu.Lock()
+	if m.closed {
+		m.mu.Unlock()
+		return nil
+	}
+	m.closed = true
+	conns := m.conns
+	watchers := m.watchers
+	m.conns = make(map[string]*pooledConn)
+	m.watchers = make(map[string]*Subscription)
+	m.mu.Unlock()
+
+	for key, sub := range watchers {
+		if sub == nil {
+			continue
+		}
+		group, serviceName, _ := strings.Cut(key, "/")
+		if err := sub.Unsubscribe(); err != nil {
+			logger.Warnf(context.Background(), "取消订阅服务失败 [%s:%s]: %v", group, serviceName, err)
+		}
+	}
+
+	var firstErr error
+	for _, pc := range conns {
+		if err := pc.conn.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	logger.Infof(context.Background(), "已关闭 %d 个gRPC连接", len(conns))
+	return firstErr
+}
@@ -0,0 +1,74 @@
+package nacos_sdk
+
+import (
+	"testing"
+
+	"github.com/nacos-group/nacos-sdk-go/v2/model"
+	"google.golang.org/grpc/connectivity"
+)
+
+func TestConnManagerReuseAndEvict(t *testing.T) {
+	m := NewConnManager()
+	var subscribed func(instances []model.Instance, err error)
+	unsubscribed := 0
+	m.subscribe = func(serviceName, group string, callback func(instances []model.Instance, err error)) (*Subscription, error) {
+		subscribed = callback
+		return newSubscription(func() error {
+			unsubscribed++
+			return nil
+		}), nil
+	}
+
+	a := model.Instance{Ip: "127.0.0.1", Port: 19001, ServiceName: "DEFAULT_GROUP@@order"}
+	b := model.Instance{Ip: "127.0.0.1", Port: 19002, ServiceName: "DEFAULT_GROUP@@order"}
+	connA, err := m.GetForInstance(a, "order", "")
+	if err != nil {
+		t.Fatalf("dial a: %v", err)
+	}
+	again, _ := m.GetForInstance(a, "order", "DEFAULT_GROUP")
+	if again != connA {
+		t.Fatalf("expected connection reuse")
+	}
+	connB, _ := m.GetForInstance(b, "order", "DEFAULT_GROUP")
+	if subscribed == nil {
+		t.Fatalf("expected service subscription")
+	}
+
+	// 实例 a 下线
+	subscribed([]model.Instance{b}, nil)
+	if connA.GetState() != connectivity.Shutdown {
+		t.Fatalf("expected connection of removed instance to be closed")
+	}
+	if connB.GetState() == connectivity.Shutdown {
+		t.Fatalf("connection of remaining instance should stay open")
+	}
+
+	if err := m.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	if connB.GetState() != connectivity.Shutdown || unsubscribed != 1 {
+		t.Fatalf("expected all connections closed and service unsubscribed")
+	}
+	if _, err := m.GetForInstance(a, "order", ""); err == nil {
+		t.Fatalf("expected error after close")
+	}
+}
+
+func TestCloseGRPCConnectionsResetsDefaultManager(t *testing.T) {
+	old := getDefaultConnManager()
+	if err := CloseGRPCConnections(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	current := getDefaultConnManager()
+	if current == old || !old.closed || current.closed {
+		t.Fatalf("expected closed manager to be replaced by a fresh one")
+	}
+	// 新的管理器仍可建立连接
+	current.subscribe = func(string, string, func([]model.Instance, error)) (*Subscription, error) {
+		return newSubscription(func() error { return nil }), nil
+	}
+	if _, err := current.GetForInstance(model.Instance{Ip: "127.0.0.1", Port: 19003}, "order", ""); err != nil {
+		t.Fatalf("dial after close: %v", err)
+	}
+	_ = CloseGRPCConnections()
+}
@@ -0,0 +1,126 @@
+package nacos_sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Dev-Umb/go-pkg/logger"
+
+	"github.com/nacos-group/nacos-sdk-go/v2/vo"
+)
+
+// DefaultDrainPeriod 摘除流量后等待调用方感知实例变更的默认时长
+const DefaultDrainPeriod = 5 * time.Second
+
+// DrainServiceInstance 将已注册实例标记为禁用且权重为0，调用方刷新实例列表后不再路由到该实例
+// 实例仍保持注册，正在处理的请求可以继续完成
+func (c *Client) DrainServiceInstance(serviceName, ip string, port uint64, group string) error {
+	client, err := c.GetNamingClient()
+	if err != nil {
+		return err
+	}
+
+	param := vo.UpdateInstanceParam{
+		Ip:          ip,
+		Port:        port,
+		ServiceName: serviceName,
+		GroupName:   group,
+		Weight:      0,
+		Enable:      false,
+		Healthy:     true,
+		Ephemeral:   true,
+	}
+	serviceKey := fmt.Sprintf("%s-%s-%d-%s", serviceName, ip, port, group)
+	c.serviceCheckMutex.Lock()
+	if info, ok := c.registeredServices[serviceKey]; ok {
+		param.Metadata = info.Metadata
+		param.ClusterName = info.Cluster
+		param.Ephemeral = info.Ephemeral
+	}
+	c.serviceCheckMutex.Unlock()
+
+	_, err = client.UpdateInstance(param)
+	if err != nil {
+		logger.Warnf(context.Background(), "摘除服务实例流量失败 [%s:%s] IP:%s Port:%d: %v", group, serviceName, ip, port, err)
+		return err
+	}
+
+	// 摘除成功后再记录权重，失败时注册自愈仍按原权重恢复
+	c.serviceCheckMutex.Lock()
+	if info, ok := c.registeredServices[serviceKey]; ok {
+		info.Weight = 0
+	}
+	c.serviceCheckMutex.Unlock()
+
+	logger.Infof(context.Background(), "已摘除服务实例流量 [%s:%s] IP:%s Port:%d", group, serviceName, ip, port)
+	return nil
+}
+
+// DeregisterAll 注销该客户端注册的所有实例
+// 先停止注册自愈，再将所有实例禁用并置权重为0，等待 drain 让调用方感知，最后逐个注销
+func (c *Client) DeregisterAll(drain time.Duration) error {
+	c.StopWatchdog()
+
+	c.serviceCheckMutex.Lock()
+	services := make([]RegisteredServiceInfo, 0, len(c.registeredServices))
+	for _, info := range c.registeredServices {
+		services = append(services, *info)
+	}
+	c.serviceCheckMutex.Unlock()
+
+	if len(services) == 0 {
+		return nil
+	}
+
+	var errs []error
+	drained := 0
+	for _, info := range services {
+		if err := c.DrainServiceInstance(info.ServiceName, info.IP, info.Port, info.Group); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		drained++
+	}
+	if drained > 0 && drain > 0 {
+		logger.Infof(context.Background(), "等待 %s 后注销 %d 个服务实例", drain, len(services))
+		time.Sleep(drain)
+	}
+
+	for _, info := range services {
+		if _, err := c.DeregisterServiceInstance(info.ServiceName, info.IP, info.Port, info.Group); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ShutdownHandler 返回服务退出时注销所有实例的处理函数，配合 shutdown.Hook 使用：
+//
+//	hook := shutdown.NewHook()
+//	hook.Close(nacos_sdk.ShutdownHandler(nacos_sdk.DefaultDrainPeriod), func() {
+//		_ = nacos_sdk.CloseGRPCConnections()
+//	})
+func (c *Client) ShutdownHandler(drain time.Duration) func() {
+	return func() {
+		if err := c.DeregisterAll(drain); err != nil {
+			logger.Errorf(context.Background(), "服务退出时注销实例失败: %v", err)
+		}
+	}
+}
+
+// DrainServiceInstance 通过默认客户端摘除服务实例流量
+func DrainServiceInstance(serviceName, ip string, port uint64, group string) error {
+	return DefaultClient().DrainServiceInstance(serviceName, ip, port, group)
+}
+
+// DeregisterAll 注销默认客户端注册的所有实例
+func DeregisterAll(drain time.Duration) error {
+	return DefaultClient().DeregisterAll(drain)
+}
+
+// ShutdownHandler 返回服务退出时注销默认客户端所有实例的处理函数
+func ShutdownHandler(drain time.Duration) func() {
+	return DefaultClient().ShutdownHandler(drain)
+}
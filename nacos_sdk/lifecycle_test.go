@@ -0,0 +1,166 @@
+package nacos_sdk
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nacos-group/nacos-sdk-go/v2/clients/naming_client"
+	"github.com/nacos-group/nacos-sdk-go/v2/model"
+	"github.com/nacos-group/nacos-sdk-go/v2/vo"
+)
+
+type fakeNamingClient struct {
+	naming_client.INamingClient
+	calls []string
+}
+
+func (f *fakeNamingClient) RegisterInstance(param vo.RegisterInstanceParam) (bool, error) {
+	f.calls = append(f.calls, "register:"+param.ServiceName)
+	return true, nil
+}
+
+func (f *fakeNamingClient) UpdateInstance(param vo.UpdateInstanceParam) (bool, error) {
+	if param.Enable || param.Weight != 0 || param.Metadata["version"] != "1.0.0" {
+		f.calls = append(f.calls, "bad-update")
+	}
+	f.calls = append(f.calls, "drain:"+param.ServiceName)
+	return true, nil
+}
+
+func (f *fakeNamingClient) DeregisterInstance(param vo.DeregisterInstanceParam) (bool, error) {
+	f.calls = append(f.calls, "deregister:"+param.ServiceName)
+	return true, nil
+}
+
+func newFakeNamingClient(c *Client) *fakeNamingClient {
+	fake := &fakeNamingClient{}
+	c.namingOnce.Do(func() { c.namingClient = fake })
+	return fake
+}
+
+func TestDeregisterAll(t *testing.T) {
+	c := NewClient(NacosConfig{})
+	fake := newFakeNamingClient(c)
+
+	if _, err := c.RegisterServiceInstance("room", "10.0.0.1", 9000, "G", map[string]string{"version": "1.0.0"}); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+
+	start := time.Now()
+	if err := c.DeregisterAll(20 * time.Millisecond); err != nil {
+		t.Fatalf("deregister all: %v", err)
+	}
+	if time.Since(start) < 20*time.Millisecond {
+		t.Fatalf("expected to wait for the drain period")
+	}
+
+	want := []string{"register:room", "drain:room", "deregister:room"}
+	if len(fake.calls) != len(want) {
+		t.Fatalf("unexpected calls: %v", fake.calls)
+	}
+	for i := range want {
+		if fake.calls[i] != want[i] {
+			t.Fatalf("unexpected calls: %v", fake.calls)
+		}
+	}
+	if len(c.registeredServices) != 0 {
+		t.Fatalf("registered services should be cleared")
+	}
+}
+
+type watchdogNamingClient struct {
+	fakeNamingClient
+	instances []model.Instance
+	err       error
+}
+
+func (f *watchdogNamingClient) GetService(param vo.GetServiceParam) (model.Service, error) {
+	if f.err != nil {
+		return model.Service{}, f.err
+	}
+	return model.Service{Name: param.ServiceName, Hosts: f.instances}, nil
+}
+
+func TestWatchdogReregistersMissingInstance(t *testing.T) {
+	c := NewClient(NacosConfig{})
+	fake := &watchdogNamingClient{}
+	c.namingOnce.Do(func() { c.namingClient = fake })
+
+	if _, err := c.RegisterServiceInstance("room", "10.0.0.1", 9000, "G", map[string]string{"version": "1.0.0"}); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+
+	var alerts []string
+	w := c.StartWatchdog(WatchdogOptions{Interval: time.Hour, FlapThreshold: 2, Alert: func(content string) error {
+		alerts = append(alerts, content)
+		return nil
+	}})
+	defer c.StopWatchdog()
+
+	fake.instances = []model.Instance{{Ip: "10.0.0.1", Port: 9000, Healthy: true}}
+	w.check()
+	if stats := w.Stats(); stats.Missing != 0 || stats.Checks != 1 {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+
+	// 查询失败时不能判断实例缺失，只记录失败
+	fake.err = errors.New("connection refused")
+	w.check()
+	w.check()
+	if stats := w.Stats(); stats.Missing != 0 || stats.Reregistered != 0 || stats.Failures != 2 {
+		t.Fatalf("query failure should not count as missing: %+v", stats)
+	}
+	fake.err = nil
+
+	// 服务端重启后实例丢失
+	fake.instances = nil
+	w.check()
+	w.check()
+	stats := w.Stats()
+	if stats.Missing != 2 || stats.Reregistered != 2 || stats.Alerts != 1 || len(alerts) != 1 {
+		t.Fatalf("unexpected stats: %+v alerts=%v", stats, alerts)
+	}
+	if fake.calls[len(fake.calls)-1] != "register:room" {
+		t.Fatalf("expected re-registration, calls: %v", fake.calls)
+	}
+
+	// 摘除流量后不再重新注册
+	if err := c.DrainServiceInstance("room", "10.0.0.1", 9000, "G"); err != nil {
+		t.Fatalf("drain: %v", err)
+	}
+	w.check()
+	if w.Stats().Missing != 2 {
+		t.Fatalf("drained instance should not be re-registered")
+	}
+}
+
+type drainFailNamingClient struct {
+	fakeNamingClient
+}
+
+func (f *drainFailNamingClient) UpdateInstance(param vo.UpdateInstanceParam) (bool, error) {
+	return false, errors.New("connection refused")
+}
+
+func TestDrainFailureKeepsWeight(t *testing.T) {
+	c := NewClient(NacosConfig{})
+	fake := &drainFailNamingClient{}
+	c.namingOnce.Do(func() { c.namingClient = fake })
+
+	if _, err := c.RegisterServiceInstance("room", "10.0.0.1", 9000, "G", nil); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	if err := c.DrainServiceInstance("room", "10.0.0.1", 9000, "G"); err == nil {
+		t.Fatalf("expected drain error")
+	}
+
+	// 摘除失败时实例仍在接收流量，注册自愈要按原权重恢复
+	c.serviceCheckMutex.Lock()
+	defer c.serviceCheckMutex.Unlock()
+	for _, info := range c.registeredServices {
+		if info.Weight == 0 {
+			t.Fatalf("weight cleared after failed drain: %+v", info)
+		}
+	}
+}
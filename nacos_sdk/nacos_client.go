@@ -6,29 +6,51 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"sync"
 
 	"github.com/Dev-Umb/go-pkg/logger"
 
 	"github.com/nacos-group/nacos-sdk-go/v2/common/constant"
 	"github.com/nacos-group/nacos-sdk-go/v2/model"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 )
 
+// grpcDialOptions GetGRPCClient 创建连接时追加的拨号选项
+var (
+	grpcDialOptionsMu sync.RWMutex
+	grpcDialOptions   []grpc.DialOption
+)
+
+// AddGRPCDialOptions 追加创建gRPC连接时使用的拨号选项，例如认证拦截器：
+//
+//	nacos_sdk.AddGRPCDialOptions(grpc.WithChainUnaryInterceptor(jwt.UnaryClientInterceptor(jwt.ForwardToken())))
+//
+// 应在服务启动时调用
+func AddGRPCDialOptions(opts ...grpc.DialOption) {
+	grpcDialOptionsMu.Lock()
+	defer grpcDialOptionsMu.Unlock()
+	grpcDialOptions = append(grpcDialOptions, opts...)
+}
+
+// globalDialOptions 返回 AddGRPCDialOptions 追加的拨号选项副本
+func globalDialOptions() []grpc.DialOption {
+	grpcDialOptionsMu.RLock()
+	defer grpcDialOptionsMu.RUnlock()
+	return append([]grpc.DialOption(nil), grpcDialOptions...)
+}
+
 // GetGRPCClient 获取gRPC客户端实例，使用泛型以支持不同类型的客户端
 // T 是gRPC客户端接口类型
 // serviceName 是要连接的服务名称
 // group 是服务所在的分组，默认为当前服务的分组
 // newClientFunc 是创建新客户端的函数
+// 同一实例的连接会被复用，实例下线后连接自动关闭，服务退出时调用 CloseGRPCConnections
 func GetGRPCClient[T any](serviceName string, groupName string, newClientFunc func(conn *grpc.ClientConn) T) (T, error) {
-	// 使用服务发现客户端获取服务实例
-	instance, err := GetHealthyInstance(serviceName, groupName)
+	conn, err := getDefaultConnManager().Get(serviceName, groupName)
 	if err != nil {
-		return *new(T), fmt.Errorf("获取服务实例失败: %v", err)
+		return *new(T), err
 	}
-
-	// 创建gRPC连接
-	return createGRPCClient(*instance, serviceName, newClientFunc)
+	return newClientFunc(conn), nil
 }
 
 // CreateGRPCClientWithInstance 使用给定的服务实例创建gRPC客户端
@@ -36,26 +58,15 @@ func GetGRPCClient[T any](serviceName string, groupName string, newClientFunc fu
 // serviceName 是服务名称（用于日志记录）
 // newClientFunc 是创建新客户端的函数
 func CreateGRPCClientWithInstance[T any](instance model.Instance, serviceName string, newClientFunc func(conn *grpc.ClientConn) T) (T, error) {
-	return createGRPCClient(instance, serviceName, newClientFunc)
-}
-
-// createGRPCClient 创建gRPC客户端的内部实现
-func createGRPCClient[T any](instance model.Instance, serviceName string, newClientFunc func(conn *grpc.ClientConn) T) (T, error) {
-	// 创建gRPC连接
-	addr := fmt.Sprintf("%s:%d", instance.Ip, instance.Port)
-	conn, err := grpc.Dial(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := getDefaultConnManager().GetForInstance(instance, serviceName, "")
 	if err != nil {
-		log.Printf("连接服务实例失败: %s, 地址: %s, 错误: %v", serviceName, addr, err)
-		return *new(T), fmt.Errorf("连接服务实例失败: %s, 错误: %v", serviceName, err)
+		return *new(T), err
 	}
-
-	// 创建客户端
-	client := newClientFunc(conn)
-	return client, nil
+	return newClientFunc(conn), nil
 }
 
 // 初始化Nacos客户端配置
-func initNacosConfig() error {
+func (c *Client) initNacosConfig() error {
 	logger.Info(context.Background(), "initNacosConfig")
 	// 获取当前工作目录
 	workDir, err := os.Getwd()
@@ -64,8 +75,12 @@ func initNacosConfig() error {
 	}
 
 	// 创建nacos目录（使用绝对路径）
-	nacosDir = filepath.Join(workDir, "nacos_sdk")
-	logDir = filepath.Join(nacosDir, "log")
+	nacosDir := filepath.Join(workDir, "nacos_sdk")
+	logDir := c.config.LogDir
+	if logDir == "" {
+		logDir = filepath.Join(nacosDir, "log")
+	}
+	cacheDir := c.config.CacheDir
 	configDir := filepath.Join(cacheDir, "config")
 
 	// 确保目录存在
@@ -89,20 +104,63 @@ func initNacosConfig() error {
 	}
 
 	// Nacos服务器地址
-	serverConfigs = []constant.ServerConfig{
-		*constant.NewServerConfig(
-			nacosAddress, nacosPort, constant.WithScheme("http")),
+	c.serverConfigs, err = c.buildServerConfigs()
+	if err != nil {
+		return err
 	}
 	// 客户端配置
-	clientConfig = constant.ClientConfig{
-		NamespaceId:          nacosNameSpace, // 如果不需要命名空间，可以留空
+	c.clientConfig = constant.ClientConfig{
+		NamespaceId:          c.config.NacosNameSpace, // 如果不需要命名空间，可以留空
 		TimeoutMs:            10000,
 		NotLoadCacheAtStart:  true,
+		DisableUseSnapShot:   true, // 获取失败时返回错误，由 GetConfigValue 从本地快照兜底
 		LogDir:               logDir,
+		CacheDir:             cacheDir,
 		LogLevel:             "debug",
 		UpdateThreadNum:      5,        // 更新线程数
 		UpdateCacheWhenEmpty: true,     // 当服务列表为空时更新缓存
 		BeatInterval:         1 * 1000, // 心跳间隔，单位毫秒（调整为更频繁的心跳）
+		Username:             c.config.Username,
+		Password:             c.config.Password,
+		AccessKey:            c.config.AccessKey,
+		SecretKey:            c.config.SecretKey,
+	}
+	if c.config.TimeoutMs > 0 {
+		c.clientConfig.TimeoutMs = c.config.TimeoutMs
+	}
+	if c.config.BeatIntervalMs > 0 {
+		c.clientConfig.BeatInterval = c.config.BeatIntervalMs
+	}
+	if c.config.LogLevel != "" {
+		c.clientConfig.LogLevel = c.config.LogLevel
 	}
 	return nil
 }
+
+// buildServerConfigs 根据配置生成服务器列表，未配置 Servers 时使用 NacosAddress:NacosPort
+func (c *Client) buildServerConfigs() ([]constant.ServerConfig, error) {
+	servers := c.config.Servers
+	if len(servers) == 0 && c.config.NacosAddress != "" {
+		servers = []ServerAddress{{Address: c.config.NacosAddress, Port: c.config.NacosPort}}
+	}
+	if len(servers) == 0 {
+		return nil, fmt.Errorf("未配置Nacos服务器地址")
+	}
+
+	scheme := c.config.Scheme
+	if scheme == "" {
+		scheme = "http"
+	}
+	configs := make([]constant.ServerConfig, 0, len(servers))
+	for _, server := range servers {
+		opts := []constant.ServerOption{constant.WithScheme(scheme)}
+		if server.Scheme != "" {
+			opts = []constant.ServerOption{constant.WithScheme(server.Scheme)}
+		}
+		if server.ContextPath != "" {
+			opts = append(opts, constant.WithContextPath(server.ContextPath))
+		}
+		configs = append(configs, *constant.NewServerConfig(server.Address, server.Port, opts...))
+	}
+	return configs, nil
+}
@@ -3,73 +3,18 @@ package nacos_sdk
 import (
 	"fmt"
 	"log"
-	"sync"
 	"time"
 
-	"github.com/nacos-group/nacos-sdk-go/v2/clients"
-	"github.com/nacos-group/nacos-sdk-go/v2/clients/naming_client"
 	"github.com/nacos-group/nacos-sdk-go/v2/model"
 	"github.com/nacos-group/nacos-sdk-go/v2/vo"
 )
 
-// 单例模式实现
-var (
-	namingClientInstance naming_client.INamingClient
-	namingOnce           sync.Once
-	namingInitErr        error
-)
-
-// GetNamingClient 获取单例服务发现客户端
-// 返回全局唯一的命名服务客户端实例和可能的错误
-func GetNamingClient() (naming_client.INamingClient, error) {
-	namingOnce.Do(func() {
-		// 初始化Nacos配置
-		if err := initNacosConfig(); err != nil {
-			namingInitErr = fmt.Errorf("初始化Nacos配置失败: %v", err)
-			return
-		}
-
-		// 创建服务发现客户端
-		client, err := clients.CreateNamingClient(map[string]interface{}{
-			"serverConfigs": serverConfigs,
-			"clientConfig":  clientConfig,
-		})
-		if err != nil {
-			namingInitErr = fmt.Errorf("创建Nacos服务发现客户端失败: %v", err)
-			return
-		}
-
-		// 等待客户端连接就绪
-		for i := 0; i < 10; i++ {
-			// 尝试获取服务列表，检查连接状态
-			_, err := client.GetAllServicesInfo(vo.GetAllServiceInfoParam{
-				PageNo:   1,
-				PageSize: 10,
-			})
-			if err == nil {
-				// 连接成功
-				namingClientInstance = client
-				log.Printf("Nacos服务发现客户端初始化成功")
-				return
-			}
-			log.Printf("等待Nacos服务发现客户端连接就绪，重试次数: %d, 错误: %v", i+1, err)
-			time.Sleep(1 * time.Second)
-		}
-
-		if namingClientInstance == nil {
-			namingInitErr = fmt.Errorf("初始化Nacos服务发现客户端超时")
-		}
-	})
-
-	return namingClientInstance, namingInitErr
-}
-
 // GetHealthyInstance 获取一个健康的服务实例
 // serviceName: 服务名称
 // group: 服务分组
 // 返回服务实例和可能的错误
-func GetHealthyInstance(serviceName, group string) (*model.Instance, error) {
-	client, err := GetNamingClient()
+func (c *Client) GetHealthyInstance(serviceName, group string) (*model.Instance, error) {
+	client, err := c.GetNamingClient()
 	if err != nil {
 		return nil, err
 	}
@@ -91,8 +36,8 @@ func GetHealthyInstance(serviceName, group string) (*model.Instance, error) {
 // group: 服务分组
 // onlyHealthy: 是否只返回健康实例
 // 返回服务实例列表和可能的错误
-func GetAllInstances(serviceName, group string, onlyHealthy bool) ([]model.Instance, error) {
-	client, err := GetNamingClient()
+func (c *Client) GetAllInstances(serviceName, group string, onlyHealthy bool) ([]model.Instance, error) {
+	client, err := c.GetNamingClient()
 	if err != nil {
 		return nil, err
 	}
@@ -115,50 +60,21 @@ func GetAllInstances(serviceName, group string, onlyHealthy bool) ([]model.Insta
 // serviceName: 服务名称
 // group: 服务分组
 // callback: 服务变更回调函数
+// 需要取消订阅时使用 Subscribe，通过返回的句柄取消
 // 返回可能的错误
-func SubscribeService(serviceName, group string, callback func(instances []model.Instance, err error)) error {
-	client, err := GetNamingClient()
-	if err != nil {
-		return err
-	}
-
-	err = client.Subscribe(&vo.SubscribeParam{
-		ServiceName:       serviceName,
-		GroupName:         group,
-		SubscribeCallback: callback,
-	})
-	if err != nil {
-		log.Printf("订阅服务失败 [%s:%s]: %v", group, serviceName, err)
-		return err
-	}
-
-	log.Printf("成功订阅服务 [%s:%s]", group, serviceName)
-	return nil
+func (c *Client) SubscribeService(serviceName, group string, callback func(instances []model.Instance, err error)) error {
+	_, err := c.subscribe(serviceName, group, callback, true)
+	return err
 }
 
 // UnsubscribeService 取消订阅服务变更
 // serviceName: 服务名称
 // group: 服务分组
 // callback: 服务变更回调函数
+// 只能取消通过 SubscribeService 订阅的回调；同一个函数字面量创建的多个闭包无法区分，此时请使用 Subscribe
 // 返回可能的错误
-func UnsubscribeService(serviceName, group string, callback func(instances []model.Instance, err error)) error {
-	client, err := GetNamingClient()
-	if err != nil {
-		return err
-	}
-
-	err = client.Unsubscribe(&vo.SubscribeParam{
-		ServiceName:       serviceName,
-		GroupName:         group,
-		SubscribeCallback: callback,
-	})
-	if err != nil {
-		log.Printf("取消订阅服务失败 [%s:%s]: %v", group, serviceName, err)
-		return err
-	}
-
-	log.Printf("成功取消订阅服务 [%s:%s]", group, serviceName)
-	return nil
+func (c *Client) UnsubscribeService(serviceName, group string, callback func(instances []model.Instance, err error)) error {
+	return c.unsubscribeLegacy(serviceName, group, callback)
 }
 
 // RegisterServiceInstance 注册服务实例
@@ -168,8 +84,24 @@ func UnsubscribeService(serviceName, group string, callback func(instances []mod
 // group: 服务分组
 // metadata: 服务元数据
 // 返回注册是否成功和可能的错误
-func RegisterServiceInstance(serviceName, ip string, port uint64, group string, metadata map[string]string) (bool, error) {
-	client, err := GetNamingClient()
+func (c *Client) RegisterServiceInstance(serviceName, ip string, port uint64, group string, metadata map[string]string) (bool, error) {
+	return c.RegisterInstance(vo.RegisterInstanceParam{
+		Ip:          ip,
+		Port:        port,
+		ServiceName: serviceName,
+		Weight:      10,
+		Enable:      true,
+		Healthy:     true,
+		Ephemeral:   true,
+		Metadata:    metadata,
+		GroupName:   group,
+	})
+}
+
+// RegisterInstance 按完整参数注册服务实例，可指定权重、集群和是否临时实例
+// 注册成功后记录实例信息，用于注册自愈和退出时注销
+func (c *Client) RegisterInstance(param vo.RegisterInstanceParam) (bool, error) {
+	client, err := c.GetNamingClient()
 	if err != nil {
 		return false, err
 	}
@@ -177,32 +109,26 @@ func RegisterServiceInstance(serviceName, ip string, port uint64, group string,
 	// 重试注册服务
 	var success bool
 	for i := 0; i < 3; i++ {
-		success, err = client.RegisterInstance(vo.RegisterInstanceParam{
-			Ip:          ip,
-			Port:        port,
-			ServiceName: serviceName,
-			Weight:      10,
-			Enable:      true,
-			Healthy:     true,
-			Ephemeral:   true,
-			Metadata:    metadata,
-			GroupName:   group,
-		})
+		success, err = client.RegisterInstance(param)
 		if err == nil && success {
-			// 注册成功，记录服务信息到全局变量
-			serviceKey := fmt.Sprintf("%s-%s-%d-%s", serviceName, ip, port, group)
-			serviceCheckMutex.Lock()
-			registeredServices[serviceKey] = &RegisteredServiceInfo{
-				ServiceName:   serviceName,
-				IP:            ip,
-				Port:          port,
-				Group:         group,
+			// 注册成功，记录服务信息
+			serviceKey := fmt.Sprintf("%s-%s-%d-%s", param.ServiceName, param.Ip, param.Port, param.GroupName)
+			c.serviceCheckMutex.Lock()
+			c.registeredServices[serviceKey] = &RegisteredServiceInfo{
+				ServiceName:   param.ServiceName,
+				IP:            param.Ip,
+				Port:          param.Port,
+				Group:         param.GroupName,
+				Cluster:       param.ClusterName,
+				Metadata:      param.Metadata,
+				Weight:        param.Weight,
+				Ephemeral:     param.Ephemeral,
 				NamingClient:  nil, // 不再保存客户端实例
 				LastHeartbeat: time.Now(),
 			}
-			serviceCheckMutex.Unlock()
+			c.serviceCheckMutex.Unlock()
 
-			log.Printf("服务注册成功 [%s:%s] IP:%s Port:%d", group, serviceName, ip, port)
+			log.Printf("服务注册成功 [%s:%s] IP:%s Port:%d", param.GroupName, param.ServiceName, param.Ip, param.Port)
 			return success, nil
 		}
 		log.Printf("注册服务失败，重试次数: %d, 错误: %v", i+1, err)
@@ -218,24 +144,30 @@ func RegisterServiceInstance(serviceName, ip string, port uint64, group string,
 // port: 服务端口
 // group: 服务分组
 // 返回注销是否成功和可能的错误
-func DeregisterServiceInstance(serviceName, ip string, port uint64, group string) (bool, error) {
-	client, err := GetNamingClient()
+func (c *Client) DeregisterServiceInstance(serviceName, ip string, port uint64, group string) (bool, error) {
+	client, err := c.GetNamingClient()
 	if err != nil {
 		return false, err
 	}
 
-	// 从记录中移除服务
-	serviceKey := fmt.Sprintf("%s-%s-%d-%s", serviceName, ip, port, group)
-	serviceCheckMutex.Lock()
-	delete(registeredServices, serviceKey)
-	serviceCheckMutex.Unlock()
-
-	success, err := client.DeregisterInstance(vo.DeregisterInstanceParam{
+	// 从记录中移除服务，注册时的集群和是否临时实例需要一并传给注销接口
+	param := vo.DeregisterInstanceParam{
 		Ip:          ip,
 		Port:        port,
 		ServiceName: serviceName,
 		GroupName:   group,
-	})
+		Ephemeral:   true,
+	}
+	serviceKey := fmt.Sprintf("%s-%s-%d-%s", serviceName, ip, port, group)
+	c.serviceCheckMutex.Lock()
+	if info, ok := c.registeredServices[serviceKey]; ok {
+		param.Cluster = info.Cluster
+		param.Ephemeral = info.Ephemeral
+	}
+	delete(c.registeredServices, serviceKey)
+	c.serviceCheckMutex.Unlock()
+
+	success, err := client.DeregisterInstance(param)
 	if err != nil {
 		log.Printf("注销服务实例失败 [%s:%s] IP:%s Port:%d: %v",
 			group, serviceName, ip, port, err)
@@ -245,3 +177,38 @@ func DeregisterServiceInstance(serviceName, ip string, port uint64, group string
 	log.Printf("服务注销成功 [%s:%s] IP:%s Port:%d", group, serviceName, ip, port)
 	return success, nil
 }
+
+// GetHealthyInstance 通过默认客户端获取一个健康的服务实例
+func GetHealthyInstance(serviceName, group string) (*model.Instance, error) {
+	return DefaultClient().GetHealthyInstance(serviceName, group)
+}
+
+// GetAllInstances 通过默认客户端获取指定服务的所有实例
+func GetAllInstances(serviceName, group string, onlyHealthy bool) ([]model.Instance, error) {
+	return DefaultClient().GetAllInstances(serviceName, group, onlyHealthy)
+}
+
+// SubscribeService 通过默认客户端订阅服务变更
+func SubscribeService(serviceName, group string, callback func(instances []model.Instance, err error)) error {
+	return DefaultClient().SubscribeService(serviceName, group, callback)
+}
+
+// UnsubscribeService 通过默认客户端取消订阅服务变更
+func UnsubscribeService(serviceName, group string, callback func(instances []model.Instance, err error)) error {
+	return DefaultClient().UnsubscribeService(serviceName, group, callback)
+}
+
+// RegisterServiceInstance 通过默认客户端注册服务实例
+func RegisterServiceInstance(serviceName, ip string, port uint64, group string, metadata map[string]string) (bool, error) {
+	return DefaultClient().RegisterServiceInstance(serviceName, ip, port, group, metadata)
+}
+
+// RegisterInstance 通过默认客户端按完整参数注册服务实例
+func RegisterInstance(param vo.RegisterInstanceParam) (bool, error) {
+	return DefaultClient().RegisterInstance(param)
+}
+
+// DeregisterServiceInstance 通过默认客户端注销服务实例
+func DeregisterServiceInstance(serviceName, ip string, port uint64, group string) (bool, error) {
+	return DefaultClient().DeregisterServiceInstance(serviceName, ip, port, group)
+}
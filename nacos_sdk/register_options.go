@@ -0,0 +1,149 @@
+package nacos_sdk
+
+import (
+	"os"
+	"runtime/debug"
+)
+
+// Version 服务版本，可在编译时注入：-ldflags "-X github.com/Dev-Umb/go-pkg/nacos_sdk.Version=1.2.0"
+// 为空时使用模块版本，仍取不到时为 1.0.0
+var Version string
+
+// 注册元数据中自动填充的键
+const (
+	MetadataVersion  = "version"
+	MetadataRevision = "revision"
+	MetadataHostname = "hostname"
+	MetadataZone     = "zone"
+	MetadataEndpoint = "endpoint"
+)
+
+// zoneEnv 可用区环境变量，WithZone 未指定时使用
+const zoneEnv = "NACOS_ZONE"
+
+// Endpoint 当前进程对外提供的一个端点，如 grpc、http、ws
+type Endpoint struct {
+	Name        string
+	Port        uint64
+	ServiceName string // 为空时为 项目名-端点名
+}
+
+// RegisterOption RegisterNacosService 的注册选项
+type RegisterOption func(*registerOptions)
+
+type registerOptions struct {
+	metadata  map[string]string
+	weight    float64
+	cluster   string
+	ephemeral bool
+	zone      string
+	endpoints []Endpoint
+	noRPC     bool
+}
+
+// WithMetadata 追加实例元数据，覆盖自动填充的同名键
+func WithMetadata(metadata map[string]string) RegisterOption {
+	return func(o *registerOptions) {
+		for k, v := range metadata {
+			o.metadata[k] = v
+		}
+	}
+}
+
+// WithWeight 设置实例权重，默认10
+func WithWeight(weight float64) RegisterOption {
+	return func(o *registerOptions) {
+		if weight > 0 {
+			o.weight = weight
+		}
+	}
+}
+
+// WithCluster 设置实例所属集群
+func WithCluster(cluster string) RegisterOption {
+	return func(o *registerOptions) {
+		o.cluster = cluster
+	}
+}
+
+// WithEphemeral 设置是否为临时实例，默认为临时实例；持久实例不依赖心跳，需要显式注销
+func WithEphemeral(ephemeral bool) RegisterOption {
+	return func(o *registerOptions) {
+		o.ephemeral = ephemeral
+	}
+}
+
+// WithZone 设置可用区元数据，默认读取环境变量 NACOS_ZONE
+func WithZone(zone string) RegisterOption {
+	return func(o *registerOptions) {
+		o.zone = zone
+	}
+}
+
+// WithEndpoint 额外注册一个命名端点，服务名为 项目名-name，例如 WithEndpoint("ws", 8081)
+func WithEndpoint(name string, port uint64) RegisterOption {
+	return WithEndpoints(Endpoint{Name: name, Port: port})
+}
+
+// WithEndpoints 额外注册多个端点
+func WithEndpoints(endpoints ...Endpoint) RegisterOption {
+	return func(o *registerOptions) {
+		o.endpoints = append(o.endpoints, endpoints...)
+	}
+}
+
+// WithoutRPC 不注册 RpcPort 对应的默认 grpc 端点，用于只提供 http/ws 的服务
+func WithoutRPC() RegisterOption {
+	return func(o *registerOptions) {
+		o.noRPC = true
+	}
+}
+
+func newRegisterOptions(opts ...RegisterOption) *registerOptions {
+	o := &registerOptions{
+		metadata:  make(map[string]string),
+		weight:    10,
+		ephemeral: true,
+		zone:      os.Getenv(zoneEnv),
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// instanceMetadata 返回端点的实例元数据：构建信息、主机名、可用区，再叠加 WithMetadata
+func (o *registerOptions) instanceMetadata(endpoint string) map[string]string {
+	metadata := buildInfoMetadata()
+	if o.zone != "" {
+		metadata[MetadataZone] = o.zone
+	}
+	metadata[MetadataEndpoint] = endpoint
+	for k, v := range o.metadata {
+		metadata[k] = v
+	}
+	return metadata
+}
+
+// buildInfoMetadata 从编译信息中读取版本和代码提交
+func buildInfoMetadata() map[string]string {
+	metadata := map[string]string{MetadataVersion: "1.0.0"}
+	info, ok := debug.ReadBuildInfo()
+	if ok && info.Main.Version != "" && info.Main.Version != "(devel)" {
+		metadata[MetadataVersion] = info.Main.Version
+	}
+	if Version != "" {
+		metadata[MetadataVersion] = Version
+	}
+	if ok {
+		for _, setting := range info.Settings {
+			if setting.Key == "vcs.revision" {
+				metadata[MetadataRevision] = setting.Value
+			}
+		}
+	}
+	if hostname, err := os.Hostname(); err == nil {
+		metadata[MetadataHostname] = hostname
+	}
+	return metadata
+}
@@ -0,0 +1,64 @@
+package nacos_sdk
+
+import (
+	"testing"
+
+	"github.com/nacos-group/nacos-sdk-go/v2/vo"
+)
+
+type registerNamingClient struct {
+	fakeNamingClient
+	params []vo.RegisterInstanceParam
+}
+
+func (f *registerNamingClient) RegisterInstance(param vo.RegisterInstanceParam) (bool, error) {
+	f.params = append(f.params, param)
+	return true, nil
+}
+
+func (f *registerNamingClient) Subscribe(param *vo.SubscribeParam) error {
+	return nil
+}
+
+func TestRegisterNacosServiceOptions(t *testing.T) {
+	c := NewClient(NacosConfig{ProjectName: "room", NacosGroup: "G", RpcPort: "9000"})
+	fake := &registerNamingClient{}
+	c.namingOnce.Do(func() { c.namingClient = fake })
+
+	Version = "2.3.4"
+	defer func() { Version = "" }()
+
+	if c.RegisterNacosService(
+		WithWeight(50),
+		WithCluster("bj"),
+		WithEphemeral(false),
+		WithZone("bj-a"),
+		WithMetadata(map[string]string{"team": "game"}),
+		WithEndpoint("ws", 9001),
+		WithEndpoints(Endpoint{Name: "http", Port: 8080, ServiceName: "room-api"}),
+	) == nil {
+		t.Fatalf("expected naming client")
+	}
+
+	if len(fake.params) != 3 {
+		t.Fatalf("expected 3 endpoints, got %+v", fake.params)
+	}
+	names := []string{"room", "room-ws", "room-api"}
+	ports := []uint64{9000, 9001, 8080}
+	for i, p := range fake.params {
+		if p.ServiceName != names[i] || p.Port != ports[i] || p.Weight != 50 || p.ClusterName != "bj" || p.Ephemeral || p.GroupName != "G" {
+			t.Fatalf("unexpected param %d: %+v", i, p)
+		}
+		if p.Metadata[MetadataVersion] != "2.3.4" || p.Metadata[MetadataZone] != "bj-a" || p.Metadata["team"] != "game" || p.Metadata[MetadataHostname] == "" {
+			t.Fatalf("unexpected metadata %d: %v", i, p.Metadata)
+		}
+	}
+	if fake.params[1].Metadata[MetadataEndpoint] != "ws" {
+		t.Fatalf("unexpected endpoint metadata: %v", fake.params[1].Metadata)
+	}
+
+	info := c.registeredServices["room-ws-"+fake.params[1].Ip+"-9001-G"]
+	if info == nil || info.Cluster != "bj" || info.Ephemeral || info.Weight != 50 {
+		t.Fatalf("unexpected registered info: %+v", info)
+	}
+}
@@ -0,0 +1,222 @@
+package nacos_sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/Dev-Umb/go-pkg/logger"
+
+	"github.com/nacos-group/nacos-sdk-go/v2/model"
+	"google.golang.org/grpc/attributes"
+	"google.golang.org/grpc/resolver"
+)
+
+// ResolverScheme gRPC解析器的scheme
+//
+//	grpc.Dial("nacos:///user-service")                       // 当前服务所在分组，round_robin
+//	grpc.Dial("nacos://GROUP/user-service?version=1.2&zone=a") // 按元数据过滤
+//	grpc.Dial("nacos:///user-service?lb=weighted")            // 按实例权重负载均衡
+const ResolverScheme = "nacos"
+
+// resolverLBParam 目标地址中选择负载均衡策略的查询参数，其余参数作为元数据过滤条件
+const resolverLBParam = "lb"
+
+func init() {
+	resolver.Register(newResolverBuilder(nil))
+}
+
+// weightAttributeKey 地址属性中存放实例权重的键
+type weightAttributeKey struct{}
+
+// InstanceWeight 获取解析器写入地址的实例权重，未设置时返回1
+func InstanceWeight(addr resolver.Address) int {
+	if w, ok := addr.BalancerAttributes.Value(weightAttributeKey{}).(int); ok && w > 0 {
+		return w
+	}
+	return 1
+}
+
+// resolverBuilder Nacos解析器构建器
+type resolverBuilder struct {
+	client    *Client // 为空时使用默认客户端
+	list      func(serviceName, group string) ([]model.Instance, error)
+	subscribe func(serviceName, group string, callback func(instances []model.Instance, err error)) (*Subscription, error)
+}
+
+func newResolverBuilder(c *Client) *resolverBuilder {
+	b := &resolverBuilder{client: c}
+	b.list = func(serviceName, group string) ([]model.Instance, error) {
+		return b.nacos().GetAllInstances(serviceName, group, false)
+	}
+	b.subscribe = func(serviceName, group string, callback func(instances []model.Instance, err error)) (*Subscription, error) {
+		return b.nacos().Subscribe(serviceName, group, callback)
+	}
+	return b
+}
+
+func (b *resolverBuilder) nacos() *Client {
+	if b.client != nil {
+		return b.client
+	}
+	return DefaultClient()
+}
+
+// ResolverBuilder 返回使用该客户端的解析器构建器，非默认客户端通过 grpc.WithResolvers 使用：
+//
+//	grpc.Dial("nacos:///user-service", grpc.WithResolvers(client.ResolverBuilder()))
+func (c *Client) ResolverBuilder() resolver.Builder {
+	return newResolverBuilder(c)
+}
+
+// Scheme 实现 resolver.Builder
+func (b *resolverBuilder) Scheme() string {
+	return ResolverScheme
+}
+
+// Build 实现 resolver.Builder，target 格式为 nacos://分组/服务名?元数据键=值
+func (b *resolverBuilder) Build(target resolver.Target, cc resolver.ClientConn, opts resolver.BuildOptions) (resolver.Resolver, error) {
+	serviceName := strings.TrimPrefix(target.URL.Path, "/")
+	if serviceName == "" {
+		return nil, fmt.Errorf("nacos解析器目标缺少服务名: %s", target.URL.String())
+	}
+	group := target.URL.Host
+	if group == "" {
+		group = b.nacos().Group()
+	}
+
+	r := &nacosResolver{
+		builder:     b,
+		cc:          cc,
+		serviceName: serviceName,
+		group:       group,
+		filters:     make(map[string]string),
+		policy:      "round_robin",
+	}
+	for key, values := range target.URL.Query() {
+		if len(values) == 0 {
+			continue
+		}
+		if key == resolverLBParam {
+			if values[0] == "weighted" {
+				r.policy = WeightedBalancerName
+			}
+			continue
+		}
+		r.filters[key] = values[0]
+	}
+
+	callback := func(instances []model.Instance, err error) {
+		if err != nil {
+			logger.Warnf(context.Background(), "nacos解析器订阅回调错误 [%s:%s]: %v", group, serviceName, err)
+			return
+		}
+		r.update(instances)
+	}
+	r.ResolveNow(resolver.ResolveNowOptions{})
+	sub, err := b.subscribe(serviceName, group, callback)
+	if err != nil {
+		return nil, fmt.Errorf("订阅服务失败 [%s:%s]: %v", group, serviceName, err)
+	}
+	r.subscription = sub
+	return r, nil
+}
+
+// nacosResolver 将Nacos实例列表推送给gRPC
+type nacosResolver struct {
+	builder      *resolverBuilder
+	cc           resolver.ClientConn
+	serviceName  string
+	group        string
+	filters      map[string]string
+	policy       string
+	subscription *Subscription
+
+	mu     sync.Mutex
+	closed bool
+}
+
+// ResolveNow 主动拉取实例列表，gRPC在连接失败时会调用
+func (r *nacosResolver) ResolveNow(resolver.ResolveNowOptions) {
+	instances, err := r.builder.list(r.serviceName, r.group)
+	if err != nil {
+		r.cc.ReportError(fmt.Errorf("获取服务实例失败 [%s:%s]: %v", r.group, r.serviceName, err))
+		return
+	}
+	r.update(instances)
+}
+
+// Close 取消订阅
+func (r *nacosResolver) Close() {
+	r.mu.Lock()
+	if r.closed {
+		r.mu.Unlock()
+		return
+	}
+	r.closed = true
+	r.mu.Unlock()
+
+	if err := r.subscription.Unsubscribe(); err != nil {
+		logger.Warnf(context.Background(), "nacos解析器取消订阅失败 [%s:%s]: %v", r.group, r.serviceName, err)
+	}
+}
+
+// update 过滤实例并更新gRPC地址列表
+func (r *nacosResolver) update(instances []model.Instance) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return
+	}
+
+	addrs := make([]resolver.Address, 0, len(instances))
+	for _, instance := range instances {
+		if !r.accept(instance) {
+			continue
+		}
+		addrs = append(addrs, resolver.Address{
+			Addr:               fmt.Sprintf("%s:%d", instance.Ip, instance.Port),
+			BalancerAttributes: attributes.New(weightAttributeKey{}, int(instance.Weight+0.5)),
+		})
+	}
+
+	state := resolver.State{
+		Addresses:     addrs,
+		ServiceConfig: r.cc.ParseServiceConfig(fmt.Sprintf(`{"loadBalancingConfig":[{%q:{}}]}`, r.policy)),
+	}
+	if err := r.cc.UpdateState(state); err != nil && len(addrs) > 0 {
+		logger.Warnf(context.Background(), "nacos解析器更新地址失败 [%s:%s]: %v", r.group, r.serviceName, err)
+	}
+	if len(addrs) == 0 {
+		r.cc.ReportError(errors.New("没有可用的服务实例: " + r.group + "/" + r.serviceName))
+	}
+}
+
+// accept 只保留启用、健康、权重大于0且元数据匹配的实例
+func (r *nacosResolver) accept(instance model.Instance) bool {
+	if !instance.Enable || !instance.Healthy || instance.Weight <= 0 {
+		return false
+	}
+	for key, value := range r.filters {
+		if instance.Metadata[key] != value {
+			return false
+		}
+	}
+	return true
+}
+
+// ResolverTarget 构造Nacos解析器目标地址，filters 为元数据过滤条件
+func ResolverTarget(serviceName, group string, filters map[string]string) string {
+	target := url.URL{Scheme: ResolverScheme, Host: group, Path: "/" + serviceName}
+	if len(filters) > 0 {
+		query := url.Values{}
+		for key, value := range filters {
+			query.Set(key, value)
+		}
+		target.RawQuery = query.Encode()
+	}
+	return target.String()
+}
@@ -0,0 +1,101 @@
+package nacos_sdk
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/nacos-group/nacos-sdk-go/v2/model"
+	"google.golang.org/grpc/attributes"
+	"google.golang.org/grpc/balancer"
+	"google.golang.org/grpc/balancer/base"
+	"google.golang.org/grpc/resolver"
+	"google.golang.org/grpc/serviceconfig"
+)
+
+type fakeClientConn struct {
+	resolver.ClientConn
+	states []resolver.State
+	errs   []error
+	config string
+}
+
+func (f *fakeClientConn) UpdateState(s resolver.State) error {
+	f.states = append(f.states, s)
+	return nil
+}
+
+func (f *fakeClientConn) ReportError(err error) {
+	f.errs = append(f.errs, err)
+}
+
+func (f *fakeClientConn) ParseServiceConfig(js string) *serviceconfig.ParseResult {
+	f.config = js
+	return &serviceconfig.ParseResult{}
+}
+
+func TestNacosResolver(t *testing.T) {
+	instances := []model.Instance{
+		{Ip: "10.0.0.1", Port: 80, Weight: 1, Enable: true, Healthy: true, Metadata: map[string]string{"zone": "a"}},
+		{Ip: "10.0.0.2", Port: 80, Weight: 3, Enable: true, Healthy: true, Metadata: map[string]string{"zone": "a"}},
+		{Ip: "10.0.0.3", Port: 80, Weight: 1, Enable: true, Healthy: true, Metadata: map[string]string{"zone": "b"}},
+		{Ip: "10.0.0.4", Port: 80, Weight: 1, Enable: true, Healthy: false, Metadata: map[string]string{"zone": "a"}},
+		{Ip: "10.0.0.5", Port: 80, Weight: 1, Enable: false, Healthy: true, Metadata: map[string]string{"zone": "a"}},
+	}
+	var callback func([]model.Instance, error)
+	b := &resolverBuilder{
+		list: func(serviceName, group string) ([]model.Instance, error) { return instances, nil },
+		subscribe: func(serviceName, group string, cb func([]model.Instance, error)) (*Subscription, error) {
+			if serviceName != "user-service" || group != "G" {
+				t.Fatalf("unexpected subscription %s/%s", group, serviceName)
+			}
+			callback = cb
+			return newSubscription(func() error { return nil }), nil
+		},
+	}
+
+	target, _ := url.Parse(ResolverTarget("user-service", "G", map[string]string{"zone": "a"}) + "&lb=weighted")
+	cc := &fakeClientConn{}
+	r, err := b.Build(resolver.Target{URL: *target}, cc, resolver.BuildOptions{})
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	defer r.Close()
+
+	addrs := cc.states[len(cc.states)-1].Addresses
+	if len(addrs) != 2 || addrs[0].Addr != "10.0.0.1:80" || InstanceWeight(addrs[1]) != 3 {
+		t.Fatalf("unexpected addresses: %+v", addrs)
+	}
+	if cc.config != `{"loadBalancingConfig":[{"nacos_weighted":{}}]}` {
+		t.Fatalf("unexpected service config: %s", cc.config)
+	}
+
+	callback(nil, nil)
+	if len(cc.states[len(cc.states)-1].Addresses) != 0 || len(cc.errs) == 0 {
+		t.Fatalf("expected empty state and error when all instances are gone")
+	}
+}
+
+type fakeSubConn struct {
+	balancer.SubConn
+	name string
+}
+
+func TestWeightedPicker(t *testing.T) {
+	a, b := &fakeSubConn{name: "a"}, &fakeSubConn{name: "b"}
+	picker := (&weightedPickerBuilder{}).Build(base.PickerBuildInfo{ReadySCs: map[balancer.SubConn]base.SubConnInfo{
+		a: {Address: resolver.Address{BalancerAttributes: attributes.New(weightAttributeKey{}, 1)}},
+		b: {Address: resolver.Address{BalancerAttributes: attributes.New(weightAttributeKey{}, 3)}},
+	}})
+
+	counts := map[string]int{}
+	for i := 0; i < 8; i++ {
+		res, err := picker.Pick(balancer.PickInfo{})
+		if err != nil {
+			t.Fatalf("pick: %v", err)
+		}
+		counts[res.SubConn.(*fakeSubConn).name]++
+	}
+	if counts["a"] != 2 || counts["b"] != 6 {
+		t.Fatalf("unexpected distribution: %v", counts)
+	}
+}
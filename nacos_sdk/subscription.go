@@ -0,0 +1,167 @@
+package nacos_sdk
+
+import (
+	"fmt"
+	"log"
+	"reflect"
+	"sync"
+
+	"github.com/nacos-group/nacos-sdk-go/v2/model"
+	"github.com/nacos-group/nacos-sdk-go/v2/vo"
+)
+
+// Subscription 服务订阅句柄，通过 Unsubscribe 取消
+type Subscription struct {
+	once   sync.Once
+	cancel func() error
+	err    error
+}
+
+// newSubscription 创建订阅句柄，cancel 只执行一次
+func newSubscription(cancel func() error) *Subscription {
+	return &Subscription{cancel: cancel}
+}
+
+// Unsubscribe 取消订阅，重复调用返回第一次的结果
+func (s *Subscription) Unsubscribe() error {
+	s.once.Do(func() { s.err = s.cancel() })
+	return s.err
+}
+
+// serviceWatch 同一服务在SDK中只订阅一次，变更分发给本地的多个回调
+// SDK按 SubscribeCallback 的地址查找回调，取消订阅时必须使用订阅时的同一个 param
+type serviceWatch struct {
+	param *vo.SubscribeParam
+
+	subscribeOnce sync.Once
+	subscribeErr  error
+
+	nextID      uint64
+	subscribers map[uint64]*subscriber
+}
+
+// subscriber 本地回调，legacy 表示通过 SubscribeService 订阅，可由 UnsubscribeService 按回调函数取消
+type subscriber struct {
+	callback func(instances []model.Instance, err error)
+	legacy   bool
+}
+
+// Subscribe 订阅服务变更，返回用于取消订阅的句柄
+// 同一服务的多个订阅共享一个SDK订阅，最后一个订阅取消时才向服务端取消订阅
+func (c *Client) Subscribe(serviceName, group string, callback func(instances []model.Instance, err error)) (*Subscription, error) {
+	return c.subscribe(serviceName, group, callback, false)
+}
+
+func (c *Client) subscribe(serviceName, group string, callback func(instances []model.Instance, err error), legacy bool) (*Subscription, error) {
+	client, err := c.GetNamingClient()
+	if err != nil {
+		return nil, err
+	}
+
+	key := watchKey(serviceName, group)
+	c.watchMu.Lock()
+	w, ok := c.watches[key]
+	if !ok {
+		w = &serviceWatch{subscribers: make(map[uint64]*subscriber)}
+		w.param = &vo.SubscribeParam{
+			ServiceName:       serviceName,
+			GroupName:         group,
+			SubscribeCallback: func(services []model.Instance, err error) { c.dispatch(w, services, err) },
+		}
+		c.watches[key] = w
+	}
+	w.nextID++
+	id := w.nextID
+	w.subscribers[id] = &subscriber{callback: callback, legacy: legacy}
+	c.watchMu.Unlock()
+
+	w.subscribeOnce.Do(func() { w.subscribeErr = client.Subscribe(w.param) })
+	if w.subscribeErr != nil {
+		c.watchMu.Lock()
+		delete(w.subscribers, id)
+		if c.watches[key] == w {
+			delete(c.watches, key)
+		}
+		c.watchMu.Unlock()
+		log.Printf("订阅服务失败 [%s:%s]: %v", group, serviceName, w.subscribeErr)
+		return nil, w.subscribeErr
+	}
+
+	c.trackSubscription(serviceName, group, 1)
+	log.Printf("成功订阅服务 [%s:%s]", group, serviceName)
+	return newSubscription(func() error { return c.unsubscribe(key, w, id) }), nil
+}
+
+// dispatch 将SDK推送的变更分发给本地回调
+func (c *Client) dispatch(w *serviceWatch, instances []model.Instance, err error) {
+	c.watchMu.Lock()
+	callbacks := make([]func([]model.Instance, error), 0, len(w.subscribers))
+	for _, s := range w.subscribers {
+		callbacks = append(callbacks, s.callback)
+	}
+	c.watchMu.Unlock()
+
+	for _, callback := range callbacks {
+		callback(instances, err)
+	}
+}
+
+// unsubscribe 移除本地回调，没有回调时取消SDK订阅
+func (c *Client) unsubscribe(key string, w *serviceWatch, id uint64) error {
+	c.watchMu.Lock()
+	if _, ok := w.subscribers[id]; !ok {
+		c.watchMu.Unlock()
+		return nil
+	}
+	delete(w.subscribers, id)
+	last := len(w.subscribers) == 0
+	if last && c.watches[key] == w {
+		delete(c.watches, key)
+	}
+	c.watchMu.Unlock()
+
+	c.trackSubscription(w.param.ServiceName, w.param.GroupName, -1)
+	if !last {
+		return nil
+	}
+
+	client, err := c.GetNamingClient()
+	if err != nil {
+		return err
+	}
+	if err := client.Unsubscribe(w.param); err != nil {
+		log.Printf("取消订阅服务失败 [%s:%s]: %v", w.param.GroupName, w.param.ServiceName, err)
+		return err
+	}
+	log.Printf("成功取消订阅服务 [%s:%s]", w.param.GroupName, w.param.ServiceName)
+	return nil
+}
+
+// unsubscribeLegacy 取消一个通过 SubscribeService 订阅、回调函数相同的订阅
+func (c *Client) unsubscribeLegacy(serviceName, group string, callback func(instances []model.Instance, err error)) error {
+	key := watchKey(serviceName, group)
+	target := reflect.ValueOf(callback).Pointer()
+
+	c.watchMu.Lock()
+	w, ok := c.watches[key]
+	var id uint64
+	if ok {
+		for sid, s := range w.subscribers {
+			if s.legacy && reflect.ValueOf(s.callback).Pointer() == target {
+				id = sid
+				break
+			}
+		}
+	}
+	c.watchMu.Unlock()
+
+	if id == 0 {
+		return fmt.Errorf("没有找到该回调的订阅 [%s:%s]", group, serviceName)
+	}
+	return c.unsubscribe(key, w, id)
+}
+
+// Subscribe 通过默认客户端订阅服务变更，见 Client.Subscribe
+func Subscribe(serviceName, group string, callback func(instances []model.Instance, err error)) (*Subscription, error) {
+	return DefaultClient().Subscribe(serviceName, group, callback)
+}
@@ -0,0 +1,103 @@
+package nacos_sdk
+
+import (
+	"testing"
+
+	"github.com/nacos-group/nacos-sdk-go/v2/model"
+	"github.com/nacos-group/nacos-sdk-go/v2/vo"
+)
+
+// subscribeNamingClient 与SDK一致，按 SubscribeCallback 的地址登记和移除回调
+type subscribeNamingClient struct {
+	fakeNamingClient
+	callbacks    map[*func([]model.Instance, error)]bool
+	subscribes   int
+	unsubscribes int
+}
+
+func (f *subscribeNamingClient) Subscribe(param *vo.SubscribeParam) error {
+	f.subscribes++
+	f.callbacks[&param.SubscribeCallback] = true
+	return nil
+}
+
+func (f *subscribeNamingClient) Unsubscribe(param *vo.SubscribeParam) error {
+	if !f.callbacks[&param.SubscribeCallback] {
+		f.calls = append(f.calls, "unknown-callback")
+	}
+	delete(f.callbacks, &param.SubscribeCallback)
+	f.unsubscribes++
+	return nil
+}
+
+func (f *subscribeNamingClient) push(instances []model.Instance) {
+	for callback := range f.callbacks {
+		(*callback)(instances, nil)
+	}
+}
+
+func TestSubscriptionFanOut(t *testing.T) {
+	c := NewClient(NacosConfig{})
+	fake := &subscribeNamingClient{callbacks: map[*func([]model.Instance, error)]bool{}}
+	c.namingOnce.Do(func() { c.namingClient = fake })
+
+	var first, second int
+	subA, err := c.Subscribe("user", "G", func([]model.Instance, error) { first++ })
+	if err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+	subB, err := c.Subscribe("user", "G", func([]model.Instance, error) { second++ })
+	if err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+	if fake.subscribes != 1 {
+		t.Fatalf("expected one SDK subscription per service, got %d", fake.subscribes)
+	}
+
+	// 取消一个订阅后，另一个仍能收到变更，SDK订阅保留
+	if err := subA.Unsubscribe(); err != nil {
+		t.Fatalf("unsubscribe: %v", err)
+	}
+	fake.push([]model.Instance{{Ip: "10.0.0.1", Port: 80}})
+	if first != 0 || second != 1 || fake.unsubscribes != 0 {
+		t.Fatalf("unexpected delivery first=%d second=%d unsubscribes=%d", first, second, fake.unsubscribes)
+	}
+	if state := c.DiscoveryState(); len(state.Subscribed) != 1 {
+		t.Fatalf("expected service to stay subscribed: %+v", state.Subscribed)
+	}
+
+	// 最后一个订阅取消时使用订阅时的同一个参数取消SDK订阅
+	if err := subB.Unsubscribe(); err != nil {
+		t.Fatalf("unsubscribe: %v", err)
+	}
+	if fake.unsubscribes != 1 || len(fake.callbacks) != 0 || len(fake.calls) != 0 {
+		t.Fatalf("SDK callback not removed: unsubscribes=%d callbacks=%d calls=%v", fake.unsubscribes, len(fake.callbacks), fake.calls)
+	}
+	if state := c.DiscoveryState(); len(state.Subscribed) != 0 {
+		t.Fatalf("expected no subscriptions: %+v", state.Subscribed)
+	}
+	_ = subB.Unsubscribe()
+	if fake.unsubscribes != 1 {
+		t.Fatalf("repeated Unsubscribe should be a no-op")
+	}
+}
+
+func TestUnsubscribeServiceLegacy(t *testing.T) {
+	c := NewClient(NacosConfig{})
+	fake := &subscribeNamingClient{callbacks: map[*func([]model.Instance, error)]bool{}}
+	c.namingOnce.Do(func() { c.namingClient = fake })
+
+	callback := func([]model.Instance, error) {}
+	if err := c.SubscribeService("user", "G", callback); err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+	if err := c.UnsubscribeService("user", "G", callback); err != nil {
+		t.Fatalf("unsubscribe: %v", err)
+	}
+	if fake.unsubscribes != 1 || len(fake.callbacks) != 0 {
+		t.Fatalf("legacy unsubscribe did not remove SDK callback")
+	}
+	if err := c.UnsubscribeService("user", "G", callback); err == nil {
+		t.Fatalf("expected error for unknown subscription")
+	}
+}
@@ -0,0 +1,260 @@
+package nacos_sdk
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Dev-Umb/go-pkg/logger"
+
+	"github.com/nacos-group/nacos-sdk-go/v2/vo"
+)
+
+// WatchdogOptions 注册自愈配置
+type WatchdogOptions struct {
+	// Interval 检查间隔，默认10秒
+	Interval time.Duration
+	// FlapThreshold/FlapWindow 同一实例在窗口内重新注册达到阈值视为抖动并告警，默认5分钟内3次
+	FlapThreshold int
+	FlapWindow    time.Duration
+	// Alert 抖动告警，例如 ding_bot.SendMsg，为空时只记录日志
+	Alert func(content string) error
+}
+
+// WatchdogStats 注册自愈统计
+type WatchdogStats struct {
+	Checks       int64     // 检查轮数
+	Missing      int64     // 发现实例缺失或不健康的次数
+	Reregistered int64     // 重新注册成功次数
+	Failures     int64     // 查询或重新注册失败次数
+	Alerts       int64     // 抖动告警次数
+	LastCheck    time.Time // 最近一次检查时间
+}
+
+// Watchdog 定期检查已注册实例是否仍在Nacos中且健康，缺失时（如服务端重启、网络分区后）重新注册
+// 只有查询成功且结果中没有该实例时才视为缺失
+type Watchdog struct {
+	c    *Client
+	opts WatchdogOptions
+
+	stopOnce sync.Once
+	stop     chan struct{}
+	done     chan struct{}
+
+	checks, missing, reregistered, failures, alerts atomic.Int64
+	lastCheck                                       atomic.Int64
+
+	mu        sync.Mutex
+	history   map[string][]time.Time // 实例重新注册时间
+	lastAlert map[string]time.Time
+}
+
+// StartWatchdog 启动注册自愈，已有的自愈任务会先停止
+func (c *Client) StartWatchdog(opts WatchdogOptions) *Watchdog {
+	if opts.Interval <= 0 {
+		opts.Interval = 10 * time.Second
+	}
+	if opts.FlapThreshold <= 0 {
+		opts.FlapThreshold = 3
+	}
+	if opts.FlapWindow <= 0 {
+		opts.FlapWindow = 5 * time.Minute
+	}
+	w := &Watchdog{
+		c:         c,
+		opts:      opts,
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+		history:   make(map[string][]time.Time),
+		lastAlert: make(map[string]time.Time),
+	}
+
+	c.serviceCheckMutex.Lock()
+	old := c.watchdog
+	c.watchdog = w
+	c.serviceCheckMutex.Unlock()
+	if old != nil {
+		old.Stop()
+	}
+
+	go w.run()
+	return w
+}
+
+// StopWatchdog 停止注册自愈
+func (c *Client) StopWatchdog() {
+	c.serviceCheckMutex.Lock()
+	w := c.watchdog
+	c.watchdog = nil
+	c.serviceCheckMutex.Unlock()
+	if w != nil {
+		w.Stop()
+	}
+}
+
+// Watchdog 返回当前运行的注册自愈任务，未启动时为 nil
+func (c *Client) Watchdog() *Watchdog {
+	c.serviceCheckMutex.Lock()
+	defer c.serviceCheckMutex.Unlock()
+	return c.watchdog
+}
+
+// Stop 停止检查并等待当前一轮结束
+func (w *Watchdog) Stop() {
+	w.stopOnce.Do(func() { close(w.stop) })
+	<-w.done
+}
+
+// Stats 返回统计信息
+func (w *Watchdog) Stats() WatchdogStats {
+	stats := WatchdogStats{
+		Checks:       w.checks.Load(),
+		Missing:      w.missing.Load(),
+		Reregistered: w.reregistered.Load(),
+		Failures:     w.failures.Load(),
+		Alerts:       w.alerts.Load(),
+	}
+	if ts := w.lastCheck.Load(); ts > 0 {
+		stats.LastCheck = time.Unix(0, ts)
+	}
+	return stats
+}
+
+func (w *Watchdog) run() {
+	defer close(w.done)
+	ticker := time.NewTicker(w.opts.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-ticker.C:
+			w.check()
+		}
+	}
+}
+
+// check 检查一轮所有已注册实例
+func (w *Watchdog) check() {
+	ctx := context.Background()
+	w.checks.Add(1)
+	w.lastCheck.Store(time.Now().UnixNano())
+
+	client, err := w.c.GetNamingClient()
+	if err != nil {
+		w.failures.Add(1)
+		logger.Warnf(ctx, "注册自愈获取Nacos客户端失败: %v", err)
+		return
+	}
+
+	w.c.serviceCheckMutex.Lock()
+	services := make(map[string]RegisteredServiceInfo, len(w.c.registeredServices))
+	for key, info := range w.c.registeredServices {
+		// 权重为0表示已摘除流量，正在退出
+		if info.Weight > 0 {
+			services[key] = *info
+		}
+	}
+	w.c.serviceCheckMutex.Unlock()
+
+	for key, info := range services {
+		// 使用 GetService 而不是 SelectInstances：后者在服务下没有实例时也返回错误，无法与查询失败区分
+		service, err := client.GetService(vo.GetServiceParam{
+			ServiceName: info.ServiceName,
+			GroupName:   info.Group,
+		})
+		if err != nil {
+			// 查询失败（如Nacos不可用、网络分区）时无法判断实例是否缺失，跳过本轮
+			w.failures.Add(1)
+			logger.Warnf(ctx, "注册自愈查询实例失败 [%s:%s]: %v", info.Group, info.ServiceName, err)
+			continue
+		}
+
+		present := false
+		for _, instance := range service.Hosts {
+			if instance.Ip == info.IP && instance.Port == info.Port && instance.Healthy &&
+				(info.Cluster == "" || instance.ClusterName == info.Cluster) {
+				present = true
+				break
+			}
+		}
+		if present {
+			w.touch(key)
+			continue
+		}
+
+		w.missing.Add(1)
+		logger.Warnf(ctx, "服务实例不在Nacos中或不健康，重新注册 [%s:%s] IP:%s Port:%d 上次确认: %s",
+			info.Group, info.ServiceName, info.IP, info.Port, info.LastHeartbeat.Format(time.RFC3339))
+		if _, err := client.RegisterInstance(vo.RegisterInstanceParam{
+			Ip:          info.IP,
+			Port:        info.Port,
+			ServiceName: info.ServiceName,
+			Weight:      info.Weight,
+			Enable:      true,
+			Healthy:     true,
+			Ephemeral:   info.Ephemeral,
+			Metadata:    info.Metadata,
+			ClusterName: info.Cluster,
+			GroupName:   info.Group,
+		}); err != nil {
+			w.failures.Add(1)
+			logger.Errorf(ctx, "重新注册服务实例失败 [%s:%s] IP:%s Port:%d: %v", info.Group, info.ServiceName, info.IP, info.Port, err)
+			continue
+		}
+		w.reregistered.Add(1)
+		w.touch(key)
+		logger.Infof(ctx, "重新注册服务实例成功 [%s:%s] IP:%s Port:%d", info.Group, info.ServiceName, info.IP, info.Port)
+		w.recordFlap(key, info)
+	}
+}
+
+// touch 更新实例的最后确认时间
+func (w *Watchdog) touch(key string) {
+	w.c.serviceCheckMutex.Lock()
+	if info, ok := w.c.registeredServices[key]; ok {
+		info.LastHeartbeat = time.Now()
+	}
+	w.c.serviceCheckMutex.Unlock()
+}
+
+// recordFlap 记录重新注册，窗口内次数达到阈值时告警，每个窗口最多告警一次
+func (w *Watchdog) recordFlap(key string, info RegisteredServiceInfo) {
+	now := time.Now()
+	w.mu.Lock()
+	history := append(w.history[key], now)
+	for len(history) > 0 && now.Sub(history[0]) > w.opts.FlapWindow {
+		history = history[1:]
+	}
+	w.history[key] = history
+	flapping := len(history) >= w.opts.FlapThreshold && now.Sub(w.lastAlert[key]) > w.opts.FlapWindow
+	if flapping {
+		w.lastAlert[key] = now
+	}
+	w.mu.Unlock()
+
+	if !flapping {
+		return
+	}
+	w.alerts.Add(1)
+	content := fmt.Sprintf("服务实例注册抖动: [%s:%s] %s:%d 在 %s 内重新注册 %d 次",
+		info.Group, info.ServiceName, info.IP, info.Port, w.opts.FlapWindow, len(history))
+	logger.Errorf(context.Background(), "%s", content)
+	if w.opts.Alert != nil {
+		if err := w.opts.Alert(content); err != nil {
+			logger.Warnf(context.Background(), "发送注册抖动告警失败: %v", err)
+		}
+	}
+}
+
+// StartWatchdog 为默认客户端启动注册自愈
+func StartWatchdog(opts WatchdogOptions) *Watchdog {
+	return DefaultClient().StartWatchdog(opts)
+}
+
+// StopWatchdog 停止默认客户端的注册自愈
+func StopWatchdog() {
+	DefaultClient().StopWatchdog()
+}
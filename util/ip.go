@@ -2,24 +2,159 @@ package util
 
 import (
 	"context"
+	"fmt"
 	"net"
+	"path/filepath"
+	"strings"
 
 	"github.com/Dev-Umb/go-pkg/logger"
 )
 
+// IPPolicy 本机对外地址的选择策略
+type IPPolicy struct {
+	// Override 显式指定的地址，非空时直接使用
+	Override string
+	// Interfaces 优先使用的网卡名，按顺序匹配，支持通配符，如 eth0、ens*
+	Interfaces []string
+	// CIDRs 优先使用的网段，按顺序匹配，如 10.0.0.0/8
+	CIDRs []string
+	// IPv6 是否允许选择IPv6地址，同等条件下IPv4优先
+	IPv6 bool
+}
+
+// IPSelection 地址选择结果
+type IPSelection struct {
+	IP        string
+	Interface string
+	Reason    string // override、interface、cidr、first、virtual 或 fallback
+}
+
+// virtualInterfacePrefixes 容器和虚拟化常见的虚拟网卡前缀，未显式指定时跳过
+var virtualInterfacePrefixes = []string{
+	"docker", "br-", "veth", "virbr", "vmnet", "vboxnet", "cni", "flannel", "cali", "vxlan", "tun", "tap", "kube-ipvs", "weave", "lxc", "utun",
+}
+
+// ifaceInfo 网卡及其地址，便于测试替换
+type ifaceInfo struct {
+	Name  string
+	Flags net.Flags
+	Addrs []net.Addr
+}
+
+// isVirtualInterface 判断是否为虚拟网卡
+func isVirtualInterface(name string) bool {
+	for _, prefix := range virtualInterfacePrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
 // 获取本机IP地址
+// 跳过回环、未启用和虚拟网卡，优先IPv4，没有可用地址时返回 127.0.0.1
 func GetLocalIP() string {
-	addrs, err := net.InterfaceAddrs()
+	selection, err := SelectIP(IPPolicy{})
 	if err != nil {
-		logger.Errorf(context.Background(), "net.InterfaceAddrs: %v", err)
-		return "127.0.0.1"
+		logger.Errorf(context.Background(), "选择本机IP失败: %v", err)
+	}
+	return selection.IP
+}
+
+// SelectIP 按策略选择本机对外地址
+// 依次为：显式指定、优先网卡、优先网段、第一个非虚拟网卡地址、虚拟网卡地址，都没有时为 127.0.0.1
+// 显式指定的地址无效时返回错误且地址为空，不回退到其他地址；无效的网段会被跳过
+func SelectIP(policy IPPolicy) (IPSelection, error) {
+	if policy.Override != "" {
+		ip := net.ParseIP(strings.TrimSpace(policy.Override))
+		if ip == nil {
+			return IPSelection{}, fmt.Errorf("无效的IP地址: %s", policy.Override)
+		}
+		return IPSelection{IP: ip.String(), Reason: "override"}, nil
+	}
+
+	interfaces, err := net.Interfaces()
+	if err != nil {
+		return IPSelection{IP: "127.0.0.1", Reason: "fallback"}, fmt.Errorf("net.Interfaces: %v", err)
+	}
+	infos := make([]ifaceInfo, 0, len(interfaces))
+	for _, iface := range interfaces {
+		addrs, err := iface.Addrs()
+		if err != nil {
+			continue
+		}
+		infos = append(infos, ifaceInfo{Name: iface.Name, Flags: iface.Flags, Addrs: addrs})
+	}
+	return selectIP(policy, infos)
+}
+
+// ipCandidate 候选地址
+type ipCandidate struct {
+	ip      net.IP
+	iface   string
+	virtual bool
+}
+
+func selectIP(policy IPPolicy, interfaces []ifaceInfo) (IPSelection, error) {
+	var candidates []ipCandidate
+	for _, iface := range interfaces {
+		if iface.Flags&net.FlagUp == 0 || iface.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+		for _, addr := range iface.Addrs {
+			ipnet, ok := addr.(*net.IPNet)
+			if !ok || ipnet.IP.IsLoopback() || ipnet.IP.IsLinkLocalUnicast() || ipnet.IP.IsUnspecified() {
+				continue
+			}
+			if ipnet.IP.To4() == nil && !policy.IPv6 {
+				continue
+			}
+			candidates = append(candidates, ipCandidate{ip: ipnet.IP, iface: iface.Name, virtual: isVirtualInterface(iface.Name)})
+		}
+	}
+	// 同等条件下IPv4优先
+	ordered := make([]ipCandidate, 0, len(candidates))
+	for _, c := range candidates {
+		if c.ip.To4() != nil {
+			ordered = append(ordered, c)
+		}
+	}
+	for _, c := range candidates {
+		if c.ip.To4() == nil {
+			ordered = append(ordered, c)
+		}
 	}
-	for _, addr := range addrs {
-		if ipnet, ok := addr.(*net.IPNet); ok && !ipnet.IP.IsLoopback() {
-			if ipnet.IP.To4() != nil {
-				return ipnet.IP.String()
+
+	// 显式指定的网卡即使是虚拟网卡也可以使用
+	for _, pattern := range policy.Interfaces {
+		for _, c := range ordered {
+			if matched, _ := filepath.Match(pattern, c.iface); matched {
+				return IPSelection{IP: c.ip.String(), Interface: c.iface, Reason: "interface"}, nil
 			}
 		}
 	}
-	return "127.0.0.1"
+
+	for _, cidr := range policy.CIDRs {
+		_, network, err := net.ParseCIDR(strings.TrimSpace(cidr))
+		if err != nil {
+			logger.Warnf(context.Background(), "忽略无效的网段: %s", cidr)
+			continue
+		}
+		for _, c := range ordered {
+			if network.Contains(c.ip) {
+				return IPSelection{IP: c.ip.String(), Interface: c.iface, Reason: "cidr"}, nil
+			}
+		}
+	}
+
+	for _, c := range ordered {
+		if !c.virtual {
+			return IPSelection{IP: c.ip.String(), Interface: c.iface, Reason: "first"}, nil
+		}
+	}
+	// 只有虚拟网卡时仍优于回环地址
+	if len(ordered) > 0 {
+		return IPSelection{IP: ordered[0].ip.String(), Interface: ordered[0].iface, Reason: "virtual"}, nil
+	}
+	return IPSelection{IP: "127.0.0.1", Reason: "fallback"}, nil
 }
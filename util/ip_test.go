@@ -0,0 +1,63 @@
+package util
+
+import (
+	"net"
+	"testing"
+)
+
+func testIface(name string, flags net.Flags, cidrs ...string) ifaceInfo {
+	info := ifaceInfo{Name: name, Flags: flags}
+	for _, cidr := range cidrs {
+		ip, network, _ := net.ParseCIDR(cidr)
+		network.IP = ip
+		info.Addrs = append(info.Addrs, network)
+	}
+	return info
+}
+
+func TestSelectIP(t *testing.T) {
+	up := net.FlagUp
+	interfaces := []ifaceInfo{
+		testIface("lo", up|net.FlagLoopback, "127.0.0.1/8"),
+		testIface("docker0", up, "172.17.0.1/16"),
+		testIface("eth0", up, "fe80::1/64", "2001:db8::10/64", "10.0.0.5/24"),
+		testIface("eth1", 0, "192.168.1.5/24"),
+		testIface("ens5", up, "192.168.2.5/24"),
+	}
+
+	cases := []struct {
+		name   string
+		policy IPPolicy
+		ip     string
+		reason string
+	}{
+		{"first skips virtual and down", IPPolicy{}, "10.0.0.5", "first"},
+		{"override", IPPolicy{Override: "1.2.3.4"}, "1.2.3.4", "override"},
+		{"interface glob", IPPolicy{Interfaces: []string{"ens*"}}, "192.168.2.5", "interface"},
+		{"explicit virtual interface", IPPolicy{Interfaces: []string{"docker0"}}, "172.17.0.1", "interface"},
+		{"cidr", IPPolicy{CIDRs: []string{"192.168.0.0/16"}}, "192.168.2.5", "cidr"},
+		{"ipv6 cidr", IPPolicy{IPv6: true, CIDRs: []string{"2001:db8::/32"}}, "2001:db8::10", "cidr"},
+		{"ipv6 disabled", IPPolicy{CIDRs: []string{"2001:db8::/32"}}, "10.0.0.5", "first"},
+		{"invalid cidr skipped", IPPolicy{CIDRs: []string{"10.0.0/8", "192.168.0.0/16"}}, "192.168.2.5", "cidr"},
+		{"only invalid cidr", IPPolicy{CIDRs: []string{"bad"}}, "10.0.0.5", "first"},
+	}
+	for _, tc := range cases {
+		got, err := selectIP(tc.policy, interfaces)
+		if tc.policy.Override != "" {
+			got, err = SelectIP(tc.policy)
+		}
+		if err != nil || got.IP != tc.ip || got.Reason != tc.reason {
+			t.Fatalf("%s: got %+v, %v", tc.name, got, err)
+		}
+	}
+
+	if got, _ := selectIP(IPPolicy{}, interfaces[:2]); got.IP != "172.17.0.1" || got.Reason != "virtual" {
+		t.Fatalf("expected virtual interface, got %+v", got)
+	}
+	if got, _ := selectIP(IPPolicy{}, interfaces[:1]); got.IP != "127.0.0.1" || got.Reason != "fallback" {
+		t.Fatalf("expected fallback, got %+v", got)
+	}
+	if got, err := SelectIP(IPPolicy{Override: "bad"}); err == nil || got.IP != "" {
+		t.Fatalf("expected invalid override error without address, got %+v, %v", got, err)
+	}
+}